@@ -18,68 +18,24 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 
 	return &Codec{
 		typeName: &name{"array", nullNamespace},
+		kind:     "array",
+		items:    itemCodec,
 		binaryDecoder: func(buf []byte) (interface{}, []byte, error) {
-			var value interface{}
-			var err error
-
-			if value, buf, err = longDecoder(buf); err != nil {
-				return nil, buf, fmt.Errorf("cannot decode Array block count: %s", err)
-			}
-			blockCount := value.(int64)
-
-			// NOTE: While below RAM optimization not necessary, many encoders will encode all
-			// array items in a single block.  We can optimize amount of RAM allocated by
-			// runtime for the array by initializing the array for that number of items.
-			initialSize := blockCount
-			if initialSize < 0 {
-				initialSize = -initialSize
-			}
-			arrayValues := make([]interface{}, 0, initialSize)
-
-			for blockCount != 0 {
-				if blockCount < 0 {
-					// NOTE: Negative block count means following long is the block size, for which
-					// we have no use.  Read its value and discard.
-					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longDecoder(buf); err != nil {
-						return nil, buf, fmt.Errorf("cannot decode Array block size: %s", err)
-					}
-				}
-				// Decode `blockCount` datum values from buffer
-				for i := int64(0); i < blockCount; i++ {
-					if value, buf, err = itemCodec.binaryDecoder(buf); err != nil {
-						return nil, buf, fmt.Errorf("cannot decode Array item %d: %s", i+1, err)
-					}
-					arrayValues = append(arrayValues, value)
-				}
-				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longDecoder(buf); err != nil {
-					return nil, buf, fmt.Errorf("cannot decode Array block count: %s", err)
+			return genericArrayBinaryDecoder(buf, itemCodec.binaryDecoder)
+		},
+		binarySkipper: func(buf []byte) ([]byte, error) {
+			return skipBlocks(buf, func(buf []byte) ([]byte, error) {
+				newBuf, err := itemCodec.binarySkipper(buf)
+				if err != nil {
+					return buf, fmt.Errorf("cannot skip Array item: %s", err)
 				}
-				blockCount = value.(int64)
-			}
-			return arrayValues, buf, nil
+				return newBuf, nil
+			})
 		},
 		binaryEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
-			var arrayValues []interface{}
-			switch i := datum.(type) {
-			case []interface{}:
-				arrayValues = i
-			default:
-				// NOTE: If given any sort of slice, zip values to items as convenience to client.
-				v := reflect.ValueOf(datum)
-				if v.Kind() != reflect.Slice {
-					return buf, fmt.Errorf("Array: expected []interface{}; received: %T", datum)
-				}
-				// NOTE: Two better alternatives to the current algorithm are:
-				//   (1) mutate the reflection tuple underneath to convert the []int, for example,
-				//       to []interface{}, with O(1) complexity
-				//   (2) use copy builtin to zip the data items over, much like what gorrd does,
-				//       with O(n) complexity, but more efficient than what's below.
-				arrayValues = make([]interface{}, v.Len())
-				for idx := 0; idx < v.Len(); idx++ {
-					arrayValues[idx] = v.Index(idx).Interface()
-				}
+			arrayValues, err := convertArray(datum)
+			if err != nil {
+				return buf, err
 			}
 			if len(arrayValues) > 0 {
 				buf, _ = longEncoder(buf, len(arrayValues))
@@ -91,5 +47,136 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 			}
 			return longEncoder(buf, 0)
 		},
+		textDecoder: func(buf []byte) (interface{}, []byte, error) {
+			arrayValues, buf, err := genericArrayTextDecoder(buf, itemCodec)
+			if err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Array: %s", err)
+			}
+			return arrayValues, buf, nil
+		},
+		textEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
+			arrayValues, err := convertArray(datum)
+			if err != nil {
+				return buf, err
+			}
+			if buf, err = genericArrayTextEncoder(buf, arrayValues, itemCodec); err != nil {
+				return buf, fmt.Errorf("cannot encode textual Array: %s", err)
+			}
+			return buf, nil
+		},
+		defaultDecoder: func(datum interface{}) (interface{}, error) {
+			items, ok := datum.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Array default value ought to be JSON array; received: %T", datum)
+			}
+			arrayValues := make([]interface{}, len(items))
+			for i, item := range items {
+				value, err := decodeDefault(itemCodec, item)
+				if err != nil {
+					return nil, fmt.Errorf("Array default value item %d ought to match items schema: %s", i+1, err)
+				}
+				arrayValues[i] = value
+			}
+			return arrayValues, nil
+		},
 	}, nil
 }
+
+// genericArrayBinaryDecoder decodes an array from buf, using itemDecoder to decode each item.
+func genericArrayBinaryDecoder(buf []byte, itemDecoder func([]byte) (interface{}, []byte, error)) (interface{}, []byte, error) {
+	var value interface{}
+	var err error
+
+	if value, buf, err = longDecoder(buf); err != nil {
+		return nil, buf, fmt.Errorf("cannot decode Array block count: %s", err)
+	}
+	blockCount := value.(int64)
+
+	// NOTE: While below RAM optimization not necessary, many encoders will encode all
+	// array items in a single block.  We can optimize amount of RAM allocated by
+	// runtime for the array by initializing the array for that number of items.
+	initialSize := blockCount
+	if initialSize < 0 {
+		initialSize = -initialSize
+	}
+	arrayValues := make([]interface{}, 0, initialSize)
+
+	for blockCount != 0 {
+		if blockCount < 0 {
+			// NOTE: Negative block count means following long is the block size, for which
+			// we have no use.  Read its value and discard.
+			blockCount = -blockCount // convert to its positive equivalent
+			if _, buf, err = longDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode Array block size: %s", err)
+			}
+		}
+		// Decode `blockCount` datum values from buffer
+		for i := int64(0); i < blockCount; i++ {
+			if value, buf, err = itemDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode Array item %d: %s", i+1, err)
+			}
+			arrayValues = append(arrayValues, value)
+		}
+		// Decode next blockCount from buffer, because there may be more blocks
+		if value, buf, err = longDecoder(buf); err != nil {
+			return nil, buf, fmt.Errorf("cannot decode Array block count: %s", err)
+		}
+		blockCount = value.(int64)
+	}
+	return arrayValues, buf, nil
+}
+
+// skipBlocks returns the byte slice following the blocks of an encoded array or map, using
+// itemSkipper to skip each item of blocks with a positive count.  Blocks with a negative count are
+// followed by their size in bytes, so they are jumped over without skipping their items one by one.
+func skipBlocks(buf []byte, itemSkipper func([]byte) ([]byte, error)) ([]byte, error) {
+	rest := buf
+	for {
+		blockCount, newBuf, err := ReadLong(rest)
+		if err != nil {
+			return buf, fmt.Errorf("cannot skip block count: %s", err)
+		}
+		rest = newBuf
+		if blockCount == 0 {
+			return rest, nil
+		}
+		if blockCount < 0 {
+			var blockSize int64
+			if blockSize, rest, err = ReadLong(rest); err != nil {
+				return buf, fmt.Errorf("cannot skip block size: %s", err)
+			}
+			if blockSize < 0 || blockSize > int64(len(rest)) {
+				return buf, fmt.Errorf("cannot skip block: size ought to be between 0 and %d; read size: %d", len(rest), blockSize)
+			}
+			rest = rest[blockSize:]
+			continue
+		}
+		for ; blockCount > 0; blockCount-- {
+			if rest, err = itemSkipper(rest); err != nil {
+				return buf, err
+			}
+		}
+	}
+}
+
+// convertArray returns the datum as a slice of empty interfaces.  If given any sort of slice, it
+// zips values to items as a convenience to the client.
+func convertArray(datum interface{}) ([]interface{}, error) {
+	if arrayValues, ok := datum.([]interface{}); ok {
+		return arrayValues, nil
+	}
+	v := reflect.ValueOf(datum)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Array: expected []interface{}; received: %T", datum)
+	}
+	// NOTE: Two better alternatives to the current algorithm are:
+	//   (1) mutate the reflection tuple underneath to convert the []int, for example,
+	//       to []interface{}, with O(1) complexity
+	//   (2) use copy builtin to zip the data items over, much like what gorrd does,
+	//       with O(n) complexity, but more efficient than what's below.
+	arrayValues := make([]interface{}, v.Len())
+	for idx := 0; idx < v.Len(); idx++ {
+		arrayValues[idx] = v.Index(idx).Interface()
+	}
+	return arrayValues, nil
+}
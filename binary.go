@@ -0,0 +1,196 @@
+package goavro
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// NOTE: The following functions append and read the binary encoding of individual Avro values
+// using Go types rather than interface{}, so generated or hand-written code may encode and decode
+// values without allocating.  Each Read function returns the decoded value along with the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+//
+// These functions are the canonical API for individual values: the code generated by goavrogen and
+// the plans used by Marshal and Unmarshal call them, and the methods of Cursor are thin wrappers
+// around them for code that prefers to keep its position in the data in a single variable.
+
+// AppendBoolean appends the binary encoding of the Avro boolean to buf.
+func AppendBoolean(buf []byte, v bool) []byte {
+	if v {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// AppendInt appends the binary encoding of the Avro int to buf.
+func AppendInt(buf []byte, v int32) []byte {
+	return AppendLong(buf, int64(v))
+}
+
+// AppendLong appends the binary encoding of the Avro long to buf.
+func AppendLong(buf []byte, v int64) []byte {
+	buf, _ = appendInt(buf, (uint64(v)<<1)^uint64(v>>longDownShift)) // only returns nil error
+	return buf
+}
+
+// AppendFloat appends the binary encoding of the Avro float to buf.
+func AppendFloat(buf []byte, v float32) []byte {
+	buf, _ = appendFloat(buf, uint64(math.Float32bits(v)), 4) // only returns nil error
+	return buf
+}
+
+// AppendDouble appends the binary encoding of the Avro double to buf.
+func AppendDouble(buf []byte, v float64) []byte {
+	buf, _ = appendFloat(buf, math.Float64bits(v), doubleEncodedLength) // only returns nil error
+	return buf
+}
+
+// AppendBytes appends the binary encoding of the Avro bytes to buf.
+func AppendBytes(buf []byte, v []byte) []byte {
+	return append(AppendLong(buf, int64(len(v))), v...)
+}
+
+// AppendString appends the binary encoding of the Avro string to buf.
+func AppendString(buf []byte, v string) []byte {
+	return append(AppendLong(buf, int64(len(v))), v...)
+}
+
+// ReadBoolean reads the binary encoding of an Avro boolean.
+func ReadBoolean(buf []byte) (bool, []byte, error) {
+	if len(buf) < 1 {
+		return false, buf, io.ErrShortBuffer
+	}
+	switch buf[0] {
+	case 0:
+		return false, buf[1:], nil
+	case 1:
+		return true, buf[1:], nil
+	default:
+		return false, buf, fmt.Errorf("boolean: expected: Go byte(0) or byte(1); received: byte(%d)", buf[0])
+	}
+}
+
+// ReadInt reads the binary encoding of an Avro int.
+func ReadInt(buf []byte) (int32, []byte, error) {
+	v, rest, err := ReadLong(buf)
+	if err != nil {
+		return 0, buf, err
+	}
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, buf, fmt.Errorf("int: decoded value out of range: %d", v)
+	}
+	return int32(v), rest, nil
+}
+
+// ReadLong reads the binary encoding of an Avro long.
+func ReadLong(buf []byte) (int64, []byte, error) {
+	var value uint64
+	var shift uint
+	for offset := 0; offset < len(buf); offset++ {
+		b := buf[offset]
+		value |= uint64(b&intMask) << shift
+		if b&intFlag == 0 {
+			return int64(value>>1) ^ -int64(value&1), buf[offset+1:], nil
+		}
+		shift += 7
+	}
+	return 0, buf, io.ErrShortBuffer
+}
+
+// ReadFloat reads the binary encoding of an Avro float.
+func ReadFloat(buf []byte) (float32, []byte, error) {
+	if len(buf) < 4 {
+		return 0, buf, io.ErrShortBuffer
+	}
+	bits := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
+	return math.Float32frombits(bits), buf[4:], nil
+}
+
+// ReadDouble reads the binary encoding of an Avro double.
+func ReadDouble(buf []byte) (float64, []byte, error) {
+	if len(buf) < doubleEncodedLength {
+		return 0, buf, io.ErrShortBuffer
+	}
+	var bits uint64
+	for i := doubleEncodedLength - 1; i >= 0; i-- {
+		bits = bits<<8 | uint64(buf[i])
+	}
+	return math.Float64frombits(bits), buf[doubleEncodedLength:], nil
+}
+
+// ReadBytes reads the binary encoding of Avro bytes.  The returned slice refers to the bytes of
+// buf, so callers that keep it after modifying buf ought to copy it.
+func ReadBytes(buf []byte) ([]byte, []byte, error) {
+	size, rest, err := ReadLong(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	if size < 0 {
+		return nil, buf, fmt.Errorf("bytes: negative length: %d", size)
+	}
+	if size > int64(len(rest)) {
+		return nil, buf, io.ErrShortBuffer
+	}
+	return rest[:size:size], rest[size:], nil
+}
+
+// ReadString reads the binary encoding of an Avro string.
+func ReadString(buf []byte) (string, []byte, error) {
+	size, rest, err := ReadLong(buf)
+	if err != nil {
+		return "", buf, err
+	}
+	if size < 0 {
+		return "", buf, fmt.Errorf("string: negative length: %d", size)
+	}
+	if size > int64(len(rest)) {
+		return "", buf, io.ErrShortBuffer
+	}
+	return string(rest[:size]), rest[size:], nil
+}
+
+// ReadFixed reads the binary encoding of an Avro fixed of the specified size.  The returned slice
+// refers to the bytes of buf, so callers that keep it after modifying buf ought to copy it.
+func ReadFixed(buf []byte, size int) ([]byte, []byte, error) {
+	if size < 0 {
+		return nil, buf, fmt.Errorf("fixed: negative size: %d", size)
+	}
+	if len(buf) < size {
+		return nil, buf, io.ErrShortBuffer
+	}
+	return buf[:size:size], buf[size:], nil
+}
+
+// ReadBlockCount reads the count of items in the next block of an Avro array or map, discarding
+// the block size that follows a negative count.  A count of zero marks the end of the array or
+// map.
+func ReadBlockCount(buf []byte) (int64, []byte, error) {
+	count, _, rest, err := readBlockHeader(buf)
+	if err != nil {
+		return 0, buf, err
+	}
+	return count, rest, nil
+}
+
+// readBlockHeader reads the count of items in the next block of an Avro array or map, along with
+// the block size in bytes that follows a negative count, or -1 when the block has no size.
+func readBlockHeader(buf []byte) (int64, int64, []byte, error) {
+	count, rest, err := ReadLong(buf)
+	if err != nil {
+		return 0, 0, buf, fmt.Errorf("cannot decode block count: %s", err)
+	}
+	if count == math.MinInt64 {
+		// NOTE: The smallest long has no positive equivalent.
+		return 0, 0, buf, fmt.Errorf("cannot decode block count: ought to be greater than %d; read count: %d", int64(math.MinInt64), count)
+	}
+	if count >= 0 {
+		return count, -1, rest, nil
+	}
+	// NOTE: Negative block count means following long is the block size.
+	size, rest, err := ReadLong(rest)
+	if err != nil {
+		return 0, 0, buf, fmt.Errorf("cannot decode block size: %s", err)
+	}
+	return -count, size, rest, nil
+}
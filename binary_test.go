@@ -0,0 +1,162 @@
+package goavro_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestBinaryAppendMatchesCodec(t *testing.T) {
+	cases := []struct {
+		schema string
+		datum  interface{}
+		buf    []byte
+	}{
+		{`"boolean"`, true, goavro.AppendBoolean(nil, true)},
+		{`"int"`, int32(-64), goavro.AppendInt(nil, -64)},
+		{`"int"`, int32(math.MaxInt32), goavro.AppendInt(nil, math.MaxInt32)},
+		{`"long"`, int64(math.MinInt64), goavro.AppendLong(nil, math.MinInt64)},
+		{`"float"`, float32(3.5), goavro.AppendFloat(nil, 3.5)},
+		{`"double"`, 3.5, goavro.AppendDouble(nil, 3.5)},
+		{`"bytes"`, []byte("abc"), goavro.AppendBytes(nil, []byte("abc"))},
+		{`"string"`, "abc", goavro.AppendString(nil, "abc")},
+	}
+	for _, c := range cases {
+		codec, err := goavro.NewCodec(c.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := codec.BinaryEncode(nil, c.datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(c.buf, expected) {
+			t.Errorf("schema: %s; Actual: %v; Expected: %v", c.schema, c.buf, expected)
+		}
+	}
+}
+
+func TestBinaryRead(t *testing.T) {
+	buf := goavro.AppendBoolean(nil, true)
+	buf = goavro.AppendInt(buf, -3)
+	buf = goavro.AppendLong(buf, 1<<40)
+	buf = goavro.AppendFloat(buf, 1.25)
+	buf = goavro.AppendDouble(buf, -2.5)
+	buf = goavro.AppendBytes(buf, []byte("xy"))
+	buf = goavro.AppendString(buf, "hello")
+	buf = append(buf, 1, 2, 3)
+
+	b, buf, err := goavro.ReadBoolean(buf)
+	if err != nil || b != true {
+		t.Fatalf("Actual: %v, %v; Expected: %v", b, err, true)
+	}
+	i, buf, err := goavro.ReadInt(buf)
+	if err != nil || i != -3 {
+		t.Fatalf("Actual: %v, %v; Expected: %v", i, err, -3)
+	}
+	l, buf, err := goavro.ReadLong(buf)
+	if err != nil || l != 1<<40 {
+		t.Fatalf("Actual: %v, %v; Expected: %v", l, err, 1<<40)
+	}
+	f, buf, err := goavro.ReadFloat(buf)
+	if err != nil || f != 1.25 {
+		t.Fatalf("Actual: %v, %v; Expected: %v", f, err, 1.25)
+	}
+	d, buf, err := goavro.ReadDouble(buf)
+	if err != nil || d != -2.5 {
+		t.Fatalf("Actual: %v, %v; Expected: %v", d, err, -2.5)
+	}
+	bs, buf, err := goavro.ReadBytes(buf)
+	if err != nil || string(bs) != "xy" {
+		t.Fatalf("Actual: %v, %v; Expected: %v", bs, err, "xy")
+	}
+	s, buf, err := goavro.ReadString(buf)
+	if err != nil || s != "hello" {
+		t.Fatalf("Actual: %v, %v; Expected: %v", s, err, "hello")
+	}
+	fixed, buf, err := goavro.ReadFixed(buf, 3)
+	if err != nil || !bytes.Equal(fixed, []byte{1, 2, 3}) {
+		t.Fatalf("Actual: %v, %v; Expected: %v", fixed, err, []byte{1, 2, 3})
+	}
+	if len(buf) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", buf, nil)
+	}
+}
+
+func TestBinaryReadFail(t *testing.T) {
+	checkFail := func(buf, rest []byte, err error, errorMessage string) {
+		t.Helper()
+		if err == nil || !strings.Contains(err.Error(), errorMessage) {
+			t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+		}
+		if !bytes.Equal(rest, buf) {
+			t.Errorf("Actual: %v; Expected: %v", rest, buf)
+		}
+	}
+
+	buf := []byte{2}
+	_, rest, err := goavro.ReadBoolean(buf)
+	checkFail(buf, rest, err, "expected: Go byte(0) or byte(1)")
+
+	buf = goavro.AppendLong(nil, math.MaxInt32+1)
+	_, rest, err = goavro.ReadInt(buf)
+	checkFail(buf, rest, err, "out of range")
+
+	buf = []byte{0x80, 0x80}
+	_, rest, err = goavro.ReadLong(buf)
+	checkFail(buf, rest, err, io.ErrShortBuffer.Error())
+
+	buf = []byte{0, 0, 0}
+	_, rest, err = goavro.ReadFloat(buf)
+	checkFail(buf, rest, err, io.ErrShortBuffer.Error())
+
+	buf = []byte{1}
+	_, rest, err = goavro.ReadBytes(buf)
+	checkFail(buf, rest, err, "negative length")
+
+	buf = []byte{6, 'a'}
+	_, rest, err = goavro.ReadString(buf)
+	checkFail(buf, rest, err, io.ErrShortBuffer.Error())
+
+	_, rest, err = goavro.ReadFixed(buf, 3)
+	checkFail(buf, rest, err, io.ErrShortBuffer.Error())
+
+	_, rest, err = goavro.ReadFixed(buf, -1)
+	checkFail(buf, rest, err, "negative size")
+}
+
+func TestBinaryReadBlockCount(t *testing.T) {
+	// NOTE: Negative block count is followed by the block size, which is discarded.
+	buf := goavro.AppendLong(goavro.AppendLong(nil, -2), 4)
+	buf = append(buf, 0xff)
+	count, rest, err := goavro.ReadBlockCount(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := count, int64(2); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	buf = goavro.AppendLong(nil, -2)
+	if _, rest, err = goavro.ReadBlockCount(buf); err == nil || !strings.Contains(err.Error(), "cannot decode block size") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot decode block size")
+	}
+	if !bytes.Equal(rest, buf) {
+		t.Errorf("Actual: %v; Expected: %v", rest, buf)
+	}
+
+	buf = goavro.AppendLong(goavro.AppendLong(nil, math.MinInt64), 0)
+	if _, rest, err = goavro.ReadBlockCount(buf); err == nil || !strings.Contains(err.Error(), "cannot decode block count") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot decode block count")
+	}
+	if !bytes.Equal(rest, buf) {
+		t.Errorf("Actual: %v; Expected: %v", rest, buf)
+	}
+}
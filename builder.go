@@ -0,0 +1,401 @@
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Null returns the schema of the Avro null type.
+func Null() Schema { return &PrimitiveSchema{Name: "null"} }
+
+// Boolean returns the schema of the Avro boolean type.
+func Boolean() Schema { return &PrimitiveSchema{Name: "boolean"} }
+
+// Int returns the schema of the Avro int type.
+func Int() Schema { return &PrimitiveSchema{Name: "int"} }
+
+// Long returns the schema of the Avro long type.
+func Long() Schema { return &PrimitiveSchema{Name: "long"} }
+
+// Float returns the schema of the Avro float type.
+func Float() Schema { return &PrimitiveSchema{Name: "float"} }
+
+// Double returns the schema of the Avro double type.
+func Double() Schema { return &PrimitiveSchema{Name: "double"} }
+
+// Bytes returns the schema of the Avro bytes type.
+func Bytes() Schema { return &PrimitiveSchema{Name: "bytes"} }
+
+// String returns the schema of the Avro string type.
+func String() Schema { return &PrimitiveSchema{Name: "string"} }
+
+// Array returns the schema of an Avro array whose items have the specified schema.
+func Array(items Schema) Schema { return &ArraySchema{Items: items} }
+
+// Map returns the schema of an Avro map whose values have the specified schema.
+func Map(values Schema) Schema { return &MapSchema{Values: values} }
+
+// UnionOf returns the schema of an Avro union of the specified member schemas.
+func UnionOf(members ...Schema) Schema { return &UnionSchema{Members: members} }
+
+// Nullable returns the schema of an Avro union of null and the specified schema.  Because null is
+// the first member of the union, a field of this schema may have a null default value.
+func Nullable(s Schema) Schema { return UnionOf(Null(), s) }
+
+// Enum returns the schema of an Avro enum with the specified full name and symbols.
+func Enum(name string, symbols ...string) Schema { return &EnumSchema{Name: name, Symbols: symbols} }
+
+// Fixed returns the schema of an Avro fixed type with the specified full name and size.
+func Fixed(name string, size int) Schema { return &FixedSchema{Name: name, Size: size} }
+
+// Logical returns the schema of the logical type annotating the specified underlying schema.  The
+// properties specify other attributes of the logical type, such as precision and scale, and may be
+// nil.
+func Logical(name string, underlying Schema, properties map[string]interface{}) Schema {
+	return &LogicalSchema{Name: name, Underlying: underlying, Properties: properties}
+}
+
+// RecordBuilder builds the schema of an Avro record one field at a time.  A RecordBuilder is
+// itself a Schema, so a record being built may be used as the type of a field of another record.
+//
+//	schema, err := goavro.NewRecord("com.acme.User").
+//		Field("id", goavro.Long()).
+//		Field("email", goavro.Nullable(goavro.String())).
+//		Build()
+type RecordBuilder struct {
+	record *RecordSchema
+	err    error // first error encountered while building the record
+}
+
+// NewRecord returns a RecordBuilder for a record with the specified full name.
+func NewRecord(name string) *RecordBuilder {
+	rb := &RecordBuilder{record: &RecordSchema{Name: name}}
+	if err := checkFullName(name); err != nil {
+		rb.err = fmt.Errorf("Record ought to have valid name: %s", err)
+	}
+	return rb
+}
+
+// Type returns "record".
+func (rb *RecordBuilder) Type() string { return "record" }
+
+// Doc sets the documentation of the record.
+func (rb *RecordBuilder) Doc(doc string) *RecordBuilder {
+	rb.record.Doc = doc
+	return rb
+}
+
+// Aliases adds aliases to the record.
+func (rb *RecordBuilder) Aliases(aliases ...string) *RecordBuilder {
+	for _, alias := range aliases {
+		if err := checkFullName(alias); err != nil && rb.err == nil {
+			rb.err = fmt.Errorf("Record %q alias ought to be valid name: %s", rb.record.Name, err)
+		}
+	}
+	rb.record.Aliases = append(rb.record.Aliases, aliases...)
+	return rb
+}
+
+// Field appends a field with the specified name and schema to the record.
+func (rb *RecordBuilder) Field(name string, s Schema) *RecordBuilder {
+	return rb.AddField(&Field{Name: name, Type: s})
+}
+
+// FieldWithDefault appends a field with the specified name, schema, and default value to the
+// record.  The default value of a union field must be a value of the union's first member.  The
+// default value of a bytes or fixed field may be a byte slice or a byte array.
+func (rb *RecordBuilder) FieldWithDefault(name string, s Schema, defaultValue interface{}) *RecordBuilder {
+	return rb.AddField(&Field{Name: name, Type: s, HasDefault: true, Default: defaultValue})
+}
+
+// AddField appends the field to the record, which allows specifying any attribute of the field.
+func (rb *RecordBuilder) AddField(field *Field) *RecordBuilder {
+	if rb.err == nil {
+		if err := checkNameComponent(field.Name); err != nil {
+			rb.err = fmt.Errorf("Record %q field %d ought to have valid name: %s", rb.record.Name, len(rb.record.Fields)+1, err)
+		} else if field.Type == nil {
+			rb.err = fmt.Errorf("Record %q field %q ought to have schema", rb.record.Name, field.Name)
+		} else {
+			for _, existing := range rb.record.Fields {
+				if existing.Name == field.Name {
+					rb.err = fmt.Errorf("Record %q field %d ought to have unique name: %q", rb.record.Name, len(rb.record.Fields)+1, field.Name)
+					break
+				}
+			}
+		}
+	}
+	rb.record.Fields = append(rb.record.Fields, field)
+	return rb
+}
+
+// Schema returns the record schema, or the first error encountered while building it.
+func (rb *RecordBuilder) Schema() (*RecordSchema, error) {
+	if rb.err != nil {
+		return nil, rb.err
+	}
+	return rb.record, nil
+}
+
+// Build returns the JSON specification of the record schema, after ensuring NewCodec accepts it.
+func (rb *RecordBuilder) Build() (string, error) {
+	if rb.err != nil {
+		return "", rb.err
+	}
+	return SchemaJSON(rb)
+}
+
+// SchemaJSON returns the JSON specification of the schema tree, after ensuring NewCodec accepts it.
+// Each named type is defined the first time it appears in the tree, and referred to by its full
+// name thereafter.
+func SchemaJSON(s Schema) (string, error) {
+	buf, err := appendSchemaJSON(nil, s, make(map[string]struct{}))
+	if err != nil {
+		return "", err
+	}
+	if _, err = NewCodec(string(buf)); err != nil {
+		return "", fmt.Errorf("cannot create codec from schema: %s", err)
+	}
+	return string(buf), nil
+}
+
+// checkFullName returns an error unless each component of the full name is a valid name.
+func checkFullName(fullName string) error {
+	for _, component := range strings.Split(fullName, ".") {
+		if err := checkNameComponent(component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendSchemaJSON appends the JSON specification of the schema tree to buf.  The defined map
+// stores the full names of the named types already defined.
+func appendSchemaJSON(buf []byte, s Schema, defined map[string]struct{}) ([]byte, error) {
+	var err error
+
+	switch v := s.(type) {
+	case *RecordBuilder:
+		record, err := v.Schema()
+		if err != nil {
+			return nil, err
+		}
+		return appendSchemaJSON(buf, record, defined)
+	case *PrimitiveSchema:
+		return strconv.AppendQuote(buf, v.Name), nil
+	case *ArraySchema:
+		buf = append(buf, `{"type":"array","items":`...)
+		if buf, err = appendSchemaJSON(buf, v.Items, defined); err != nil {
+			return nil, fmt.Errorf("Array items ought to be valid schema: %s", err)
+		}
+		return append(buf, '}'), nil
+	case *MapSchema:
+		buf = append(buf, `{"type":"map","values":`...)
+		if buf, err = appendSchemaJSON(buf, v.Values, defined); err != nil {
+			return nil, fmt.Errorf("Map values ought to be valid schema: %s", err)
+		}
+		return append(buf, '}'), nil
+	case *UnionSchema:
+		buf = append(buf, '[')
+		for i, member := range v.Members {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendSchemaJSON(buf, member, defined); err != nil {
+				return nil, fmt.Errorf("Union item %d ought to be valid schema: %s", i+1, err)
+			}
+		}
+		return append(buf, ']'), nil
+	case *LogicalSchema:
+		// NOTE: Write the underlying schema, then insert the logical type attributes before its
+		// closing brace.
+		var underlying []byte
+		if underlying, err = appendSchemaJSON(nil, v.Underlying, defined); err != nil {
+			return nil, fmt.Errorf("logical type %q ought to have valid underlying schema: %s", v.Name, err)
+		}
+		if underlying[0] == '"' {
+			underlying = append(append([]byte(`{"type":`), underlying...), '}')
+		} else if underlying[0] != '{' {
+			return nil, fmt.Errorf("logical type %q ought to annotate primitive, named, array, or map schema", v.Name)
+		}
+		buf = append(buf, underlying[:len(underlying)-1]...)
+		buf = append(buf, `,"logicalType":`...)
+		buf = strconv.AppendQuote(buf, v.Name)
+		if buf, err = appendSchemaProperties(buf, v.Properties); err != nil {
+			return nil, fmt.Errorf("logical type %q ought to have valid properties: %s", v.Name, err)
+		}
+		return append(buf, '}'), nil
+	case *EnumSchema:
+		if err = checkFullName(v.Name); err != nil {
+			return nil, fmt.Errorf("Enum ought to have valid name: %s", err)
+		}
+		if _, ok := defined[v.Name]; ok {
+			return strconv.AppendQuote(buf, v.Name), nil
+		}
+		defined[v.Name] = struct{}{}
+		buf = append(buf, `{"type":"enum","name":`...)
+		buf = strconv.AppendQuote(buf, v.Name)
+		buf = appendSchemaDocAndAliases(buf, v.Doc, v.Aliases)
+		buf = append(buf, `,"symbols":`...)
+		if buf, err = appendJSON(buf, v.Symbols); err != nil {
+			return nil, err
+		}
+		if v.Default != "" {
+			buf = append(buf, `,"default":`...)
+			buf = strconv.AppendQuote(buf, v.Default)
+		}
+		return append(buf, '}'), nil
+	case *FixedSchema:
+		if err = checkFullName(v.Name); err != nil {
+			return nil, fmt.Errorf("Fixed ought to have valid name: %s", err)
+		}
+		if _, ok := defined[v.Name]; ok {
+			return strconv.AppendQuote(buf, v.Name), nil
+		}
+		defined[v.Name] = struct{}{}
+		buf = append(buf, `{"type":"fixed","name":`...)
+		buf = strconv.AppendQuote(buf, v.Name)
+		buf = appendSchemaDocAndAliases(buf, "", v.Aliases)
+		buf = append(buf, `,"size":`...)
+		buf = strconv.AppendInt(buf, int64(v.Size), 10)
+		return append(buf, '}'), nil
+	case *RecordSchema:
+		if err = checkFullName(v.Name); err != nil {
+			return nil, fmt.Errorf("Record ought to have valid name: %s", err)
+		}
+		if _, ok := defined[v.Name]; ok {
+			return strconv.AppendQuote(buf, v.Name), nil
+		}
+		defined[v.Name] = struct{}{}
+		buf = append(buf, `{"type":"record","name":`...)
+		buf = strconv.AppendQuote(buf, v.Name)
+		buf = appendSchemaDocAndAliases(buf, v.Doc, v.Aliases)
+		buf = append(buf, `,"fields":[`...)
+		for i, field := range v.Fields {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendFieldJSON(buf, field, defined); err != nil {
+				return nil, fmt.Errorf("Record %q field %d ought to be valid: %s", v.Name, i+1, err)
+			}
+		}
+		return append(buf, "]}"...), nil
+	case nil:
+		return nil, fmt.Errorf("schema ought not be nil")
+	default:
+		return nil, fmt.Errorf("unknown schema type: %T", s)
+	}
+}
+
+// appendFieldJSON appends the JSON specification of the record field to buf.
+func appendFieldJSON(buf []byte, field *Field, defined map[string]struct{}) ([]byte, error) {
+	if err := checkNameComponent(field.Name); err != nil {
+		return nil, err
+	}
+	var err error
+	buf = append(buf, `{"name":`...)
+	buf = strconv.AppendQuote(buf, field.Name)
+	buf = appendSchemaDocAndAliases(buf, field.Doc, field.Aliases)
+	buf = append(buf, `,"type":`...)
+	if buf, err = appendSchemaJSON(buf, field.Type, defined); err != nil {
+		return nil, err
+	}
+	if field.HasDefault {
+		buf = append(buf, `,"default":`...)
+		if buf, err = appendDefaultJSON(buf, field.Default); err != nil {
+			return nil, fmt.Errorf("default value: %s", err)
+		}
+	}
+	if field.Order != "" && field.Order != "ascending" {
+		buf = append(buf, `,"order":`...)
+		buf = strconv.AppendQuote(buf, field.Order)
+	}
+	return append(buf, '}'), nil
+}
+
+// appendSchemaDocAndAliases appends the doc and aliases attributes to buf, when they are not
+// empty.
+func appendSchemaDocAndAliases(buf []byte, doc string, aliases []string) []byte {
+	if doc != "" {
+		buf = append(buf, `,"doc":`...)
+		buf, _ = appendJSON(buf, doc) // marshaling a string cannot fail
+	}
+	if len(aliases) > 0 {
+		buf = append(buf, `,"aliases":`...)
+		buf, _ = appendJSON(buf, aliases) // marshaling a string slice cannot fail
+	}
+	return buf
+}
+
+// appendSchemaProperties appends each property to buf as an additional attribute, in sorted order.
+func appendSchemaProperties(buf []byte, properties map[string]interface{}) ([]byte, error) {
+	if len(properties) == 0 {
+		return buf, nil
+	}
+	// NOTE: encoding/json sorts map keys, so marshal the map and splice its members into the
+	// enclosing object.
+	blob, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, ',')
+	return append(buf, blob[1:len(blob)-1]...), nil
+}
+
+// appendDefaultJSON appends the JSON encoding of the default value of a field to buf.  Byte slices
+// and byte arrays, the values of bytes and fixed, are encoded as strings whose code points are the
+// values of the bytes, as the Avro specification requires, rather than as base64 strings, including
+// when they are the items of arrays or the values of maps and records.
+func appendDefaultJSON(buf []byte, value interface{}) ([]byte, error) {
+	var err error
+	switch v := value.(type) {
+	case []interface{}:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendDefaultJSON(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf = append(buf, '{')
+		for i, key := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf, _ = appendJSON(buf, key) // marshaling a string cannot fail
+			buf = append(buf, ':')
+			if buf, err = appendDefaultJSON(buf, v[key]); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	}
+	rv := reflect.ValueOf(value)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return appendTextBytes(buf, b), nil
+	}
+	return appendJSON(buf, value)
+}
+
+// appendJSON appends the JSON encoding of the value to buf.
+func appendJSON(buf []byte, value interface{}) ([]byte, error) {
+	blob, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, blob...), nil
+}
@@ -0,0 +1,142 @@
+package goavro_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func testBuilderFail(t *testing.T, rb *goavro.RecordBuilder, errorMessage string) {
+	_, err := rb.Build()
+	if err == nil || !strings.Contains(err.Error(), errorMessage) {
+		t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+	}
+}
+
+func TestBuilderRecord(t *testing.T) {
+	schema, err := goavro.NewRecord("com.acme.User").
+		Field("id", goavro.Long()).
+		Field("email", goavro.Nullable(goavro.String())).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := schema, `{"type":"record","name":"com.acme.User","fields":[{"name":"id","type":"long"},{"name":"email","type":["null","string"]}]}`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if _, err = goavro.NewCodec(schema); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilderAllTypes(t *testing.T) {
+	address := goavro.NewRecord("com.acme.Address").
+		Doc("postal address").
+		Field("city", goavro.String())
+
+	schema, err := goavro.NewRecord("com.acme.Account").
+		Aliases("com.acme.Customer").
+		Field("active", goavro.Boolean()).
+		Field("count", goavro.Int()).
+		Field("ratio", goavro.Float()).
+		Field("score", goavro.Double()).
+		Field("blob", goavro.Bytes()).
+		Field("kind", goavro.Enum("com.acme.Kind", "A", "B")).
+		Field("hash", goavro.Fixed("com.acme.MD5", 16)).
+		Field("tags", goavro.Array(goavro.String())).
+		Field("attrs", goavro.Map(goavro.Long())).
+		Field("home", address).
+		Field("work", goavro.Nullable(address)).
+		Field("either", goavro.UnionOf(goavro.Int(), goavro.String())).
+		Field("created", goavro.Logical("timestamp-millis", goavro.Long(), nil)).
+		Field("amount", goavro.Logical("decimal", goavro.Bytes(), map[string]interface{}{"precision": 4, "scale": 2})).
+		FieldWithDefault("note", goavro.Nullable(goavro.String()), nil).
+		AddField(&goavro.Field{Name: "rank", Type: goavro.Int(), Doc: "sort rank", Order: "descending", Aliases: []string{"position"}}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"record","name":"com.acme.Account","aliases":["com.acme.Customer"],"fields":[` +
+		`{"name":"active","type":"boolean"},` +
+		`{"name":"count","type":"int"},` +
+		`{"name":"ratio","type":"float"},` +
+		`{"name":"score","type":"double"},` +
+		`{"name":"blob","type":"bytes"},` +
+		`{"name":"kind","type":{"type":"enum","name":"com.acme.Kind","symbols":["A","B"]}},` +
+		`{"name":"hash","type":{"type":"fixed","name":"com.acme.MD5","size":16}},` +
+		`{"name":"tags","type":{"type":"array","items":"string"}},` +
+		`{"name":"attrs","type":{"type":"map","values":"long"}},` +
+		`{"name":"home","type":{"type":"record","name":"com.acme.Address","doc":"postal address","fields":[{"name":"city","type":"string"}]}},` +
+		`{"name":"work","type":["null","com.acme.Address"]},` +
+		`{"name":"either","type":["int","string"]},` +
+		`{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},` +
+		`{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}},` +
+		`{"name":"note","type":["null","string"],"default":null},` +
+		`{"name":"rank","doc":"sort rank","aliases":["position"],"type":"int","order":"descending"}]}`
+	if actual := schema; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestBuilderSchemaJSONRoundTrip(t *testing.T) {
+	original := `{"type":"record","name":"LongList","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","LongList"],"default":null}]}`
+	schema, err := goavro.ParseSchema(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := goavro.SchemaJSON(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := original; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestBuilderBytesDefaults(t *testing.T) {
+	schema, err := goavro.NewRecord("r").
+		Field("x", goavro.Int()).
+		FieldWithDefault("b", goavro.Bytes(), []byte{0xff, 'a'}).
+		FieldWithDefault("f", goavro.Fixed("f2", 2), [2]byte{0x00, 0xfe}).
+		FieldWithDefault("a", goavro.Array(goavro.Bytes()), []interface{}{[]byte{0x80}}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `"default":"\u00ffa"`; !strings.Contains(schema, expected) {
+		t.Errorf("Actual: %v; Expected: %v", schema, expected)
+	}
+
+	// NOTE: Data written without the fields is decoded using their default values.
+	codec, err := goavro.NewResolvingCodec(`{"type":"record","name":"r","fields":[{"name":"x","type":"int"}]}`, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _, err := codec.BinaryDecode([]byte{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"x": int32(1),
+		"b": []byte{0xff, 'a'},
+		"f": []byte{0x00, 0xfe},
+		"a": []interface{}{[]byte{0x80}},
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Actual: %v; Expected: %v", value, expected)
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	testBuilderFail(t, goavro.NewRecord("com.acme.&User").Field("id", goavro.Long()), "Record ought to have valid name: schema name ought to start with")
+	testBuilderFail(t, goavro.NewRecord("User").Aliases("9Person").Field("id", goavro.Long()), `Record "User" alias ought to be valid name`)
+	testBuilderFail(t, goavro.NewRecord("User").Field("", goavro.Long()), `Record "User" field 1 ought to have valid name`)
+	testBuilderFail(t, goavro.NewRecord("User").Field("id", nil), `Record "User" field "id" ought to have schema`)
+	testBuilderFail(t, goavro.NewRecord("User").Field("id", goavro.Long()).Field("id", goavro.Int()), `Record "User" field 2 ought to have unique name: "id"`)
+	testBuilderFail(t, goavro.NewRecord("User").Field("kind", goavro.Enum("Kind&")), `Enum ought to have valid name`)
+	testBuilderFail(t, goavro.NewRecord("User").FieldWithDefault("id", goavro.Long(), "thirteen"), "cannot create codec from schema")
+	testBuilderFail(t, goavro.NewRecord("User"), "cannot create codec from schema")
+	testBuilderFail(t, goavro.NewRecord("User").Field("inner", goavro.NewRecord("Inner").Field("x", goavro.Int()).Field("x", goavro.Int())), `Record "Inner" field 2 ought to have unique name`)
+}
@@ -0,0 +1,91 @@
+package goavro
+
+import (
+	"strconv"
+)
+
+// CanonicalSchema returns the Parsing Canonical Form of the Codec's schema, as described by the
+// Avro specification.  Two schemas that differ only in attributes that do not affect how data is
+// read, such as doc strings, aliases, default values, logical types, or the order of attributes,
+// have the same Parsing Canonical Form.
+//
+// In Parsing Canonical Form, named types are referred to by their full names, attributes other than
+// name, type, fields, symbols, items, values, and size are removed, those attributes are written in
+// that order, primitive types are written as simple strings, and all insignificant whitespace is
+// removed.  Each named type is only defined the first time it appears in the schema, and is
+// referred to by its full name thereafter.
+func (c Codec) CanonicalSchema() string {
+	return string(appendCanonicalSchema(nil, &c, make(map[string]struct{})))
+}
+
+// appendCanonicalSchema appends the Parsing Canonical Form of the codec's schema to buf.  The
+// defined map stores the full names of the named types already defined in the schema.
+func appendCanonicalSchema(buf []byte, c *Codec, defined map[string]struct{}) []byte {
+	// NOTE: Logical types do not affect how data is read, so are not part of the canonical form.
+	if c.underlying != nil {
+		c = c.underlying
+	}
+
+	switch c.kind {
+	case "array":
+		buf = append(buf, `{"type":"array","items":`...)
+		buf = appendCanonicalSchema(buf, c.items, defined)
+		return append(buf, '}')
+	case "map":
+		buf = append(buf, `{"type":"map","values":`...)
+		buf = appendCanonicalSchema(buf, c.items, defined)
+		return append(buf, '}')
+	case "union":
+		buf = append(buf, '[')
+		for i, member := range c.members {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendCanonicalSchema(buf, member, defined)
+		}
+		return append(buf, ']')
+	case "enum", "fixed", "record":
+		fullName := c.typeName.fullName
+		if _, ok := defined[fullName]; ok {
+			return strconv.AppendQuote(buf, fullName)
+		}
+		defined[fullName] = struct{}{}
+
+		buf = append(buf, `{"name":`...)
+		buf = strconv.AppendQuote(buf, fullName)
+		buf = append(buf, `,"type":`...)
+		buf = strconv.AppendQuote(buf, c.kind)
+
+		switch c.kind {
+		case "enum":
+			buf = append(buf, `,"symbols":[`...)
+			for i, symbol := range c.symbols {
+				if i > 0 {
+					buf = append(buf, ',')
+				}
+				buf = strconv.AppendQuote(buf, symbol)
+			}
+			buf = append(buf, ']')
+		case "fixed":
+			buf = append(buf, `,"size":`...)
+			buf = strconv.AppendInt(buf, int64(c.size), 10)
+		case "record":
+			buf = append(buf, `,"fields":[`...)
+			for i, field := range c.fields {
+				if i > 0 {
+					buf = append(buf, ',')
+				}
+				buf = append(buf, `{"name":`...)
+				buf = strconv.AppendQuote(buf, field.name)
+				buf = append(buf, `,"type":`...)
+				buf = appendCanonicalSchema(buf, field.codec, defined)
+				buf = append(buf, '}')
+			}
+			buf = append(buf, ']')
+		}
+		return append(buf, '}')
+	default:
+		// primitive type
+		return strconv.AppendQuote(buf, c.kind)
+	}
+}
@@ -0,0 +1,68 @@
+package goavro_test
+
+import (
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func testCanonicalSchema(t *testing.T, schema, expected string) {
+	c, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := c.CanonicalSchema(); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestCanonicalSchemaPrimitives(t *testing.T) {
+	testCanonicalSchema(t, `"null"`, `"null"`)
+	testCanonicalSchema(t, `{"type":"boolean"}`, `"boolean"`)
+	testCanonicalSchema(t, `{ "type" : "int", "doc" : "some int" }`, `"int"`)
+	testCanonicalSchema(t, `{"type":"string","logicalType":"uuid"}`, `"string"`)
+	testCanonicalSchema(t, `{"type":"long","logicalType":"timestamp-millis"}`, `"long"`)
+}
+
+func TestCanonicalSchemaComplex(t *testing.T) {
+	testCanonicalSchema(t, `{"type":"array","items":{"type":"int"}}`, `{"type":"array","items":"int"}`)
+	testCanonicalSchema(t, `{"values":"double","type":"map"}`, `{"type":"map","values":"double"}`)
+	testCanonicalSchema(t, `["null", {"type":"string"}]`, `["null","string"]`)
+}
+
+func TestCanonicalSchemaNamed(t *testing.T) {
+	testCanonicalSchema(t, `{"type":"fixed","name":"f1","namespace":"com.example","size":16,"aliases":["f0"],"logicalType":"decimal","precision":4}`,
+		`{"name":"com.example.f1","type":"fixed","size":16}`)
+	testCanonicalSchema(t, `{"symbols":["alpha","bravo"],"type":"enum","name":"e1","doc":"an enum","default":"alpha"}`,
+		`{"name":"e1","type":"enum","symbols":["alpha","bravo"]}`)
+	testCanonicalSchema(t, `{
+  "type": "record",
+  "name": "LongList",
+  "namespace": "com.example",
+  "doc": "linked list of 64-bit values",
+  "aliases": ["LinkedLongs"],
+  "fields": [
+    {"name": "value", "type": "long", "doc": "the value"},
+    {"name": "next", "type": ["null", "LongList"], "default": null, "aliases": ["following"]}
+  ]
+}`, `{"name":"com.example.LongList","type":"record","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","com.example.LongList"]}]}`)
+}
+
+func TestCanonicalSchemaNamedTypeDefinedOnce(t *testing.T) {
+	testCanonicalSchema(t, `{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"a","type":{"type":"fixed","name":"md5","size":16}},{"name":"b","type":"md5"}]}`,
+		`{"name":"com.example.r1","type":"record","fields":[{"name":"a","type":{"name":"com.example.md5","type":"fixed","size":16}},{"name":"b","type":"com.example.md5"}]}`)
+}
+
+func TestCanonicalSchemaEquivalent(t *testing.T) {
+	c1, err := goavro.NewCodec(`{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"a","type":"int"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := goavro.NewCodec(`{"name":"com.example.r1","type":"record","doc":"same record","fields":[{"type":{"type":"int"},"name":"a","default":13}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := c1.CanonicalSchema(), c2.CanonicalSchema(); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
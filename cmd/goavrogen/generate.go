@@ -0,0 +1,674 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/karrick/goavro"
+)
+
+// generator accumulates the declarations generated for one or more schemas.
+type generator struct {
+	pkg     string
+	decls   bytes.Buffer
+	imports map[string]struct{}
+	named   map[string]string // Go type name of each Avro named type already declared
+	goNames map[string]string // Avro full name or union description using each Go type name
+	schemas map[string]struct{}
+	tops    []goavro.Schema // top-level schemas already added, in order
+	temp    int             // counter used to generate unique variable names
+}
+
+func newGenerator(pkg string) *generator {
+	return &generator{
+		pkg:     pkg,
+		imports: map[string]struct{}{"github.com/karrick/goavro": {}},
+		named:   make(map[string]string),
+		goNames: make(map[string]string),
+		schemas: make(map[string]struct{}),
+	}
+}
+
+// addSchema generates the declarations for the named types of the schema specification, along
+// with constants holding the schema and its fingerprint.  The specification may refer to the named
+// types defined by the schemas already added, in which case the constant holds the schema with the
+// definitions of those types, so it may be used without them.
+func (g *generator) addSchema(spec string) error {
+	codec, err := goavro.NewCodec(spec)
+	if err != nil {
+		if spec, err = g.standaloneSchema(spec, err); err != nil {
+			return err
+		}
+		if codec, err = goavro.NewCodec(spec); err != nil {
+			return err
+		}
+	}
+	schema := codec.Schema()
+
+	top := schema
+	if ls, ok := top.(*goavro.LogicalSchema); ok {
+		top = ls.Underlying
+	}
+	switch top.(type) {
+	case *goavro.RecordSchema, *goavro.EnumSchema, *goavro.FixedSchema:
+	default:
+		return fmt.Errorf("top-level schema ought to be record, enum, or fixed; received: %s", schema.Type())
+	}
+
+	typeName, err := g.goType(schema)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.schemas[typeName]; ok {
+		return nil
+	}
+	g.schemas[typeName] = struct{}{}
+	g.tops = append(g.tops, schema)
+
+	var compact bytes.Buffer
+	if err = json.Compact(&compact, []byte(spec)); err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.decls, "// %sAvroSchema is the Avro schema of %s.\n", typeName, typeName)
+	literal := strconv.Quote(compact.String())
+	if !strings.Contains(compact.String(), "`") {
+		literal = "`" + compact.String() + "`"
+	}
+	fmt.Fprintf(&g.decls, "const %sAvroSchema = %s\n\n", typeName, literal)
+	fmt.Fprintf(&g.decls, "// %sAvroFingerprint is the Rabin fingerprint of the canonical form of %sAvroSchema.\n", typeName, typeName)
+	fmt.Fprintf(&g.decls, "const %sAvroFingerprint uint64 = %#x\n\n", typeName, codec.Fingerprint())
+	return nil
+}
+
+// standaloneSchema returns the specification of the schema, which refers to named types defined
+// by the schemas already added, including the definitions of those types.  It returns the error
+// from parsing the schema by itself when the schema does not parse with those types either.
+func (g *generator) standaloneSchema(spec string, parseErr error) (string, error) {
+	if len(g.tops) == 0 {
+		return "", parseErr
+	}
+	// NOTE: Members of a union share one symbol table, so parse the schema as the last member of
+	// a union whose other members are the schemas already added, each named type being defined by
+	// the first member that uses it.
+	prefix, err := goavro.SchemaJSON(&goavro.UnionSchema{Members: g.tops})
+	if err != nil {
+		return "", parseErr
+	}
+	union, err := goavro.NewCodec(prefix[:len(prefix)-1] + "," + spec + "]")
+	if err != nil {
+		return "", parseErr
+	}
+	members := union.Schema().(*goavro.UnionSchema).Members
+	return goavro.SchemaJSON(members[len(members)-1])
+}
+
+// source returns the formatted source code of the generated declarations.
+func (g *generator) source() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by goavrogen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkg)
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		if !strings.Contains(path, ".") {
+			fmt.Fprintf(&buf, "\t%q\n", path) // standard library packages first
+		}
+	}
+	buf.WriteString("\n")
+	for _, path := range imports {
+		if strings.Contains(path, ".") {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(g.decls.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// body accumulates the statements of a generated method.
+type body struct {
+	bytes.Buffer
+	usesErr bool // true when the statements refer to the err variable
+}
+
+// tempName returns a variable name unique within the generated code.
+func (g *generator) tempName(prefix string) string {
+	g.temp++
+	return prefix + strconv.Itoa(g.temp)
+}
+
+// reserveGoName records that the Go type name is used for the Avro type, and returns an error when
+// it is already used for another Avro type.
+func (g *generator) reserveGoName(goName, avroName string) error {
+	if other, ok := g.goNames[goName]; ok && other != avroName {
+		return fmt.Errorf("cannot use Go type name %q for both %s and %s", goName, other, avroName)
+	}
+	g.goNames[goName] = avroName
+	return nil
+}
+
+// goType returns the Go type for the schema, generating the declarations of the named types and
+// union wrappers it refers to.
+func (g *generator) goType(s goavro.Schema) (string, error) {
+	switch s := s.(type) {
+	case *goavro.PrimitiveSchema:
+		switch s.Name {
+		case "null":
+			return "struct{}", nil
+		case "boolean":
+			return "bool", nil
+		case "int":
+			return "int32", nil
+		case "long":
+			return "int64", nil
+		case "float":
+			return "float32", nil
+		case "double":
+			return "float64", nil
+		case "bytes":
+			return "[]byte", nil
+		default:
+			return "string", nil
+		}
+	case *goavro.LogicalSchema:
+		switch s.Name {
+		case "date", "timestamp-millis", "timestamp-micros":
+			g.imports["time"] = struct{}{}
+			return "time.Time", nil
+		case "time-millis", "time-micros":
+			g.imports["time"] = struct{}{}
+			return "time.Duration", nil
+		default:
+			return g.goType(s.Underlying)
+		}
+	case *goavro.RecordSchema:
+		return g.declareRecord(s)
+	case *goavro.EnumSchema:
+		return g.declareEnum(s)
+	case *goavro.FixedSchema:
+		return g.declareFixed(s)
+	case *goavro.ArraySchema:
+		items, err := g.goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + items, nil
+	case *goavro.MapSchema:
+		values, err := g.goType(s.Values)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + values, nil
+	case *goavro.UnionSchema:
+		if _, valueIndex, ok := nullableUnion(s); ok {
+			value, err := g.goType(s.Members[valueIndex])
+			if err != nil {
+				return "", err
+			}
+			return "*" + value, nil
+		}
+		return g.declareUnion(s)
+	default:
+		return "", fmt.Errorf("cannot generate Go type for schema: %T", s)
+	}
+}
+
+// nullableUnion returns the positions of the null member and the other member of a union of null
+// and one other type.
+func nullableUnion(s *goavro.UnionSchema) (int, int, bool) {
+	if len(s.Members) != 2 {
+		return 0, 0, false
+	}
+	for i, member := range s.Members {
+		if member.Type() == "null" {
+			if _, ok := member.(*goavro.LogicalSchema); !ok {
+				return i, 1 - i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// encodeValue writes the statements that append the encoding of the Go expression to b.
+func (g *generator) encodeValue(w *body, s goavro.Schema, expr string) error {
+	switch s := s.(type) {
+	case *goavro.PrimitiveSchema:
+		switch s.Name {
+		case "null":
+		case "boolean":
+			fmt.Fprintf(w, "b = goavro.AppendBoolean(b, %s)\n", expr)
+		case "int":
+			fmt.Fprintf(w, "b = goavro.AppendInt(b, %s)\n", expr)
+		case "long":
+			fmt.Fprintf(w, "b = goavro.AppendLong(b, %s)\n", expr)
+		case "float":
+			fmt.Fprintf(w, "b = goavro.AppendFloat(b, %s)\n", expr)
+		case "double":
+			fmt.Fprintf(w, "b = goavro.AppendDouble(b, %s)\n", expr)
+		case "bytes":
+			fmt.Fprintf(w, "b = goavro.AppendBytes(b, %s)\n", expr)
+		case "string":
+			fmt.Fprintf(w, "b = goavro.AppendString(b, %s)\n", expr)
+		}
+		return nil
+	case *goavro.LogicalSchema:
+		switch s.Name {
+		case "date":
+			// NOTE: A date has no time zone, so use the calendar date in the location of the value.
+			fmt.Fprintf(w, "b = goavro.AppendInt(b, int32(time.Date(%s.Year(), %s.Month(), %s.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400))\n", expr, expr, expr)
+		case "timestamp-millis":
+			fmt.Fprintf(w, "b = goavro.AppendLong(b, %s.UnixMilli())\n", expr)
+		case "timestamp-micros":
+			fmt.Fprintf(w, "b = goavro.AppendLong(b, %s.UnixMicro())\n", expr)
+		case "time-millis":
+			fmt.Fprintf(w, "b = goavro.AppendInt(b, int32(%s/time.Millisecond))\n", expr)
+		case "time-micros":
+			fmt.Fprintf(w, "b = goavro.AppendLong(b, int64(%s/time.Microsecond))\n", expr)
+		default:
+			return g.encodeValue(w, s.Underlying, expr)
+		}
+		return nil
+	case *goavro.ArraySchema:
+		item := g.tempName("v")
+		fmt.Fprintf(w, "if len(%s) > 0 {\nb = goavro.AppendLong(b, int64(len(%s)))\nfor _, %s := range %s {\n", expr, expr, item, expr)
+		if err := g.encodeValue(w, s.Items, item); err != nil {
+			return err
+		}
+		w.WriteString("}\n}\nb = goavro.AppendLong(b, 0)\n")
+		return nil
+	case *goavro.MapSchema:
+		key, value := g.tempName("k"), g.tempName("v")
+		fmt.Fprintf(w, "if len(%s) > 0 {\nb = goavro.AppendLong(b, int64(len(%s)))\nfor %s, %s := range %s {\nb = goavro.AppendString(b, %s)\n", expr, expr, key, value, expr, key)
+		if err := g.encodeValue(w, s.Values, value); err != nil {
+			return err
+		}
+		w.WriteString("}\n}\nb = goavro.AppendLong(b, 0)\n")
+		return nil
+	case *goavro.UnionSchema:
+		if nullIndex, valueIndex, ok := nullableUnion(s); ok {
+			fmt.Fprintf(w, "if %s == nil {\nb = goavro.AppendLong(b, %d)\n} else {\nb = goavro.AppendLong(b, %d)\n", expr, nullIndex, valueIndex)
+			if err := g.encodeValue(w, s.Members[valueIndex], "(*"+expr+")"); err != nil {
+				return err
+			}
+			w.WriteString("}\n")
+			return nil
+		}
+	}
+	// NOTE: Named types and union wrappers encode themselves.
+	w.usesErr = true
+	fmt.Fprintf(w, "if b, err = %s.MarshalAvro(b); err != nil {\nreturn buf, err\n}\n", expr)
+	return nil
+}
+
+// decodeValue writes the statements that decode a value from b into the Go expression, which ought
+// to be addressable.
+func (g *generator) decodeValue(w *body, s goavro.Schema, expr string) error {
+	switch s := s.(type) {
+	case *goavro.PrimitiveSchema:
+		switch s.Name {
+		case "null":
+			return nil
+		case "bytes":
+			// NOTE: Copy the bytes, so the decoded value does not refer to the caller's buffer.
+			v := g.tempName("v")
+			w.usesErr = true
+			fmt.Fprintf(w, "var %s []byte\nif %s, b, err = goavro.ReadBytes(b); err != nil {\nreturn buf, err\n}\n%s = append([]byte(nil), %s...)\n", v, v, expr, v)
+			return nil
+		}
+		w.usesErr = true
+		fmt.Fprintf(w, "if %s, b, err = goavro.Read%s(b); err != nil {\nreturn buf, err\n}\n", expr, exportedName(s.Name))
+		return nil
+	case *goavro.LogicalSchema:
+		var rawType, convert string
+		switch s.Name {
+		case "date":
+			rawType, convert = "Int", "time.Unix(int64(%s)*86400, 0).UTC()"
+		case "timestamp-millis":
+			rawType, convert = "Long", "time.UnixMilli(%s).UTC()"
+		case "timestamp-micros":
+			rawType, convert = "Long", "time.UnixMicro(%s).UTC()"
+		case "time-millis":
+			rawType, convert = "Int", "time.Duration(%s) * time.Millisecond"
+		case "time-micros":
+			rawType, convert = "Long", "time.Duration(%s) * time.Microsecond"
+		default:
+			return g.decodeValue(w, s.Underlying, expr)
+		}
+		v := g.tempName("v")
+		w.usesErr = true
+		fmt.Fprintf(w, "var %s int%d\nif %s, b, err = goavro.Read%s(b); err != nil {\nreturn buf, err\n}\n%s = %s\n", v, map[string]int{"Int": 32, "Long": 64}[rawType], v, rawType, expr, fmt.Sprintf(convert, v))
+		return nil
+	case *goavro.ArraySchema:
+		goType, err := g.goType(s)
+		if err != nil {
+			return err
+		}
+		count, item := g.tempName("n"), g.tempName("v")
+		w.usesErr = true
+		fmt.Fprintf(w, "%s = make(%s, 0)\nfor {\nvar %s int64\nif %s, b, err = goavro.ReadBlockCount(b); err != nil {\nreturn buf, err\n}\nif %s == 0 {\nbreak\n}\nfor ; %s > 0; %s-- {\nvar %s %s\n", expr, goType, count, count, count, count, count, item, goType[2:])
+		if err = g.decodeValue(w, s.Items, item); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s = append(%s, %s)\n}\n}\n", expr, expr, item)
+		return nil
+	case *goavro.MapSchema:
+		goType, err := g.goType(s)
+		if err != nil {
+			return err
+		}
+		count, key, value := g.tempName("n"), g.tempName("k"), g.tempName("v")
+		w.usesErr = true
+		fmt.Fprintf(w, "%s = make(%s)\nfor {\nvar %s int64\nif %s, b, err = goavro.ReadBlockCount(b); err != nil {\nreturn buf, err\n}\nif %s == 0 {\nbreak\n}\nfor ; %s > 0; %s-- {\nvar %s string\nif %s, b, err = goavro.ReadString(b); err != nil {\nreturn buf, err\n}\nvar %s %s\n", expr, goType, count, count, count, count, count, key, key, value, goType[len("map[string]"):])
+		if err = g.decodeValue(w, s.Values, value); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s[%s] = %s\n}\n}\n", expr, key, value)
+		return nil
+	case *goavro.UnionSchema:
+		if nullIndex, valueIndex, ok := nullableUnion(s); ok {
+			goType, err := g.goType(s)
+			if err != nil {
+				return err
+			}
+			index := g.tempName("i")
+			g.imports["fmt"] = struct{}{}
+			w.usesErr = true
+			fmt.Fprintf(w, "var %s int64\nif %s, b, err = goavro.ReadLong(b); err != nil {\nreturn buf, err\n}\nswitch %s {\ncase %d:\n%s = nil\ncase %d:\n%s = new(%s)\n", index, index, index, nullIndex, expr, valueIndex, expr, goType[1:])
+			if err = g.decodeValue(w, s.Members[valueIndex], "(*"+expr+")"); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "default:\nreturn buf, fmt.Errorf(\"cannot decode Union: index ought to be %d or %d; read index: %%d\", %s)\n}\n", nullIndex, valueIndex, index)
+			return nil
+		}
+	}
+	// NOTE: Named types and union wrappers decode themselves.
+	w.usesErr = true
+	fmt.Fprintf(w, "if b, err = %s.UnmarshalAvro(b); err != nil {\nreturn buf, err\n}\n", expr)
+	return nil
+}
+
+// writeMethods writes the MarshalAvro and UnmarshalAvro methods, whose statements are provided.
+func (g *generator) writeMethods(w *bytes.Buffer, receiver, description string, encode, decode *body) {
+	fmt.Fprintf(w, "// MarshalAvro appends the binary Avro encoding of the %s to buf.  On error, it returns the\n// original byte slice.\n", description)
+	fmt.Fprintf(w, "func (%s) MarshalAvro(buf []byte) ([]byte, error) {\nb := buf\n", receiver)
+	if encode.usesErr {
+		w.WriteString("var err error\n")
+	}
+	w.Write(encode.Bytes())
+	w.WriteString("return b, nil\n}\n\n")
+
+	fmt.Fprintf(w, "// UnmarshalAvro decodes the binary Avro encoding of the %s from buf, and returns the byte\n// slice with the decoded bytes consumed.  On error, it returns the original byte slice.\n", description)
+	fmt.Fprintf(w, "func (%s) UnmarshalAvro(buf []byte) ([]byte, error) {\nb := buf\n", receiver)
+	if decode.usesErr {
+		w.WriteString("var err error\n")
+	}
+	w.Write(decode.Bytes())
+	w.WriteString("return b, nil\n}\n\n")
+}
+
+// writeNamedTypeMethods writes the AvroFullName and AvroFingerprint methods, which report the named
+// type, so Codec.Marshal and Codec.Unmarshal only use the MarshalAvro and UnmarshalAvro methods of
+// the Go type for that named type.
+func writeNamedTypeMethods(w *bytes.Buffer, receiver, description, fullName string, s goavro.Schema) error {
+	spec, err := goavro.SchemaJSON(s)
+	if err != nil {
+		return err
+	}
+	codec, err := goavro.NewCodec(spec)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "// AvroFullName returns the full name of the Avro %s.\n", description)
+	fmt.Fprintf(w, "func (%s) AvroFullName() string {\nreturn %q\n}\n\n", receiver, fullName)
+	fmt.Fprintf(w, "// AvroFingerprint returns the Rabin fingerprint of the canonical form of the schema of the\n// Avro %s.\n", description)
+	fmt.Fprintf(w, "func (%s) AvroFingerprint() uint64 {\nreturn %#x\n}\n\n", receiver, codec.Fingerprint())
+	return nil
+}
+
+// declareRecord generates the struct and methods for the record, and returns its Go type name.
+func (g *generator) declareRecord(s *goavro.RecordSchema) (string, error) {
+	if typeName, ok := g.named[s.Name]; ok {
+		return typeName, nil
+	}
+	typeName := exportedName(shortName(s.Name))
+	if err := g.reserveGoName(typeName, s.Name); err != nil {
+		return "", err
+	}
+	g.named[s.Name] = typeName // register before fields, which may refer back to this record
+
+	var decl bytes.Buffer
+	fmt.Fprintf(&decl, "// %s is the Go representation of the Avro record %s.", typeName, s.Name)
+	writeDoc(&decl, s.Doc)
+	fmt.Fprintf(&decl, "type %s struct {\n", typeName)
+
+	encode, decode := new(body), new(body)
+	fieldNames := make(map[string]string, len(s.Fields))
+	for _, field := range s.Fields {
+		fieldName := exportedName(field.Name)
+		if other, ok := fieldNames[fieldName]; ok {
+			return "", fmt.Errorf("cannot use Go field name %q for both %q and %q of record %s", fieldName, other, field.Name, s.Name)
+		}
+		fieldNames[fieldName] = field.Name
+
+		fieldType, err := g.goType(field.Type)
+		if err != nil {
+			return "", fmt.Errorf("record %s field %q: %s", s.Name, field.Name, err)
+		}
+		if field.Doc != "" {
+			fmt.Fprintf(&decl, "// %s\n", strings.Replace(field.Doc, "\n", "\n// ", -1))
+		}
+		fmt.Fprintf(&decl, "%s %s `avro:%q`\n", fieldName, fieldType, field.Name)
+
+		if err = g.encodeValue(encode, field.Type, "r."+fieldName); err != nil {
+			return "", err
+		}
+		if err = g.decodeValue(decode, field.Type, "r."+fieldName); err != nil {
+			return "", err
+		}
+	}
+	decl.WriteString("}\n\n")
+	g.writeMethods(&decl, "r *"+typeName, "record", encode, decode)
+	if err := writeNamedTypeMethods(&decl, "r *"+typeName, "record", s.Name, s); err != nil {
+		return "", err
+	}
+	g.decls.Write(decl.Bytes())
+	return typeName, nil
+}
+
+// declareEnum generates the type, constants, and methods for the enum, and returns its Go type
+// name.
+func (g *generator) declareEnum(s *goavro.EnumSchema) (string, error) {
+	if typeName, ok := g.named[s.Name]; ok {
+		return typeName, nil
+	}
+	typeName := exportedName(shortName(s.Name))
+	if err := g.reserveGoName(typeName, s.Name); err != nil {
+		return "", err
+	}
+	g.named[s.Name] = typeName
+	g.imports["fmt"] = struct{}{}
+
+	var decl bytes.Buffer
+	fmt.Fprintf(&decl, "// %s is the Go representation of the Avro enum %s.", typeName, s.Name)
+	writeDoc(&decl, s.Doc)
+	fmt.Fprintf(&decl, "type %s string\n\n// Symbols of the %s enum.\nconst (\n", typeName, typeName)
+	constants := make([]string, len(s.Symbols))
+	for i, symbol := range s.Symbols {
+		constants[i] = typeName + exportedName(symbol)
+		fmt.Fprintf(&decl, "%s %s = %q\n", constants[i], typeName, symbol)
+	}
+	decl.WriteString(")\n\n")
+
+	fmt.Fprintf(&decl, "// MarshalAvro appends the binary Avro encoding of the enum to buf.  On error, it returns the\n// original byte slice.\n")
+	fmt.Fprintf(&decl, "func (e %s) MarshalAvro(buf []byte) ([]byte, error) {\nswitch e {\n", typeName)
+	for i, constant := range constants {
+		fmt.Fprintf(&decl, "case %s:\nreturn goavro.AppendLong(buf, %d), nil\n", constant, i)
+	}
+	fmt.Fprintf(&decl, "}\nreturn buf, fmt.Errorf(\"cannot encode Enum %%q: value ought to be member of symbols: %v; %%q\", %q, string(e))\n}\n\n", s.Symbols, s.Name)
+
+	fmt.Fprintf(&decl, "// UnmarshalAvro decodes the binary Avro encoding of the enum from buf, and returns the byte\n// slice with the decoded bytes consumed.  On error, it returns the original byte slice.\n")
+	fmt.Fprintf(&decl, "func (e *%s) UnmarshalAvro(buf []byte) ([]byte, error) {\nindex, b, err := goavro.ReadLong(buf)\nif err != nil {\nreturn buf, err\n}\nswitch index {\n", typeName)
+	for i, constant := range constants {
+		fmt.Fprintf(&decl, "case %d:\n*e = %s\n", i, constant)
+	}
+	fmt.Fprintf(&decl, "default:\nreturn buf, fmt.Errorf(\"cannot decode Enum %%q: index ought to be between 0 and %d; read index: %%d\", %q, index)\n}\nreturn b, nil\n}\n\n", len(constants)-1, s.Name)
+	if err := writeNamedTypeMethods(&decl, "e "+typeName, "enum", s.Name, s); err != nil {
+		return "", err
+	}
+	g.decls.Write(decl.Bytes())
+	return typeName, nil
+}
+
+// declareFixed generates the type and methods for the fixed, and returns its Go type name.
+func (g *generator) declareFixed(s *goavro.FixedSchema) (string, error) {
+	if typeName, ok := g.named[s.Name]; ok {
+		return typeName, nil
+	}
+	typeName := exportedName(shortName(s.Name))
+	if err := g.reserveGoName(typeName, s.Name); err != nil {
+		return "", err
+	}
+	g.named[s.Name] = typeName
+
+	var decl bytes.Buffer
+	fmt.Fprintf(&decl, "// %s is the Go representation of the Avro fixed %s.\ntype %s [%d]byte\n\n", typeName, s.Name, typeName, s.Size)
+	fmt.Fprintf(&decl, "// MarshalAvro appends the binary Avro encoding of the fixed to buf.\n")
+	fmt.Fprintf(&decl, "func (f %s) MarshalAvro(buf []byte) ([]byte, error) {\nreturn append(buf, f[:]...), nil\n}\n\n", typeName)
+	fmt.Fprintf(&decl, "// UnmarshalAvro decodes the binary Avro encoding of the fixed from buf, and returns the byte\n// slice with the decoded bytes consumed.  On error, it returns the original byte slice.\n")
+	fmt.Fprintf(&decl, "func (f *%s) UnmarshalAvro(buf []byte) ([]byte, error) {\nv, b, err := goavro.ReadFixed(buf, %d)\nif err != nil {\nreturn buf, err\n}\ncopy(f[:], v)\nreturn b, nil\n}\n\n", typeName, s.Size)
+	if err := writeNamedTypeMethods(&decl, "f "+typeName, "fixed", s.Name, s); err != nil {
+		return "", err
+	}
+	g.decls.Write(decl.Bytes())
+	return typeName, nil
+}
+
+// declareUnion generates the wrapper struct and methods for a union other than a union of null and
+// one other type, and returns its Go type name.  The wrapper has a field for each member other
+// than null, and an Index field holding the position of the member whose field holds the value.
+func (g *generator) declareUnion(s *goavro.UnionSchema) (string, error) {
+	memberNames := make([]string, len(s.Members))
+	avroNames := make([]string, len(s.Members))
+	for i, member := range s.Members {
+		memberNames[i] = memberName(member)
+		avroNames[i] = avroName(member)
+	}
+	typeName := "Union" + strings.Join(memberNames, "")
+	description := "[" + strings.Join(avroNames, ",") + "]"
+	if _, ok := g.goNames[typeName]; ok {
+		return typeName, g.reserveGoName(typeName, description)
+	}
+	if err := g.reserveGoName(typeName, description); err != nil {
+		return "", err
+	}
+	g.imports["fmt"] = struct{}{}
+
+	var decl bytes.Buffer
+	fmt.Fprintf(&decl, "// %s is the Go representation of the Avro union:\n//\n//\t%s\n//\n// Index holds the position of the member of the union whose field holds the value.\ntype %s struct {\nIndex int\n", typeName, description, typeName)
+	encode, decode := new(body), new(body)
+	decode.usesErr = true
+	fmt.Fprintf(encode, "switch u.Index {\n")
+	fmt.Fprintf(decode, "var index int64\nif index, b, err = goavro.ReadLong(b); err != nil {\nreturn buf, err\n}\nswitch index {\n")
+	for i, member := range s.Members {
+		fmt.Fprintf(encode, "case %d:\nb = goavro.AppendLong(b, %d)\n", i, i)
+		fmt.Fprintf(decode, "case %d:\n", i)
+		if member.Type() == "null" {
+			continue
+		}
+		memberType, err := g.goType(member)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&decl, "%s %s\n", memberNames[i], memberType)
+		if err = g.encodeValue(encode, member, "u."+memberNames[i]); err != nil {
+			return "", err
+		}
+		if err = g.decodeValue(decode, member, "u."+memberNames[i]); err != nil {
+			return "", err
+		}
+	}
+	decl.WriteString("}\n\n")
+	fmt.Fprintf(encode, "default:\nreturn buf, fmt.Errorf(\"cannot encode Union: index ought to be between 0 and %d; received: %%d\", u.Index)\n}\n", len(s.Members)-1)
+	fmt.Fprintf(decode, "default:\nreturn buf, fmt.Errorf(\"cannot decode Union: index ought to be between 0 and %d; read index: %%d\", index)\n}\nu.Index = int(index)\n", len(s.Members)-1)
+
+	g.writeMethods(&decl, "u *"+typeName, "union", encode, decode)
+	g.decls.Write(decl.Bytes())
+	return typeName, nil
+}
+
+// memberName returns the name of the wrapper field for the union member.
+func memberName(s goavro.Schema) string {
+	switch s := s.(type) {
+	case *goavro.PrimitiveSchema:
+		return exportedName(s.Name)
+	case *goavro.LogicalSchema:
+		switch s.Name {
+		case "date", "timestamp-millis", "timestamp-micros", "time-millis", "time-micros":
+			return exportedName(strings.Replace(s.Name, "-", "_", -1))
+		}
+		return memberName(s.Underlying)
+	case *goavro.RecordSchema:
+		return exportedName(shortName(s.Name))
+	case *goavro.EnumSchema:
+		return exportedName(shortName(s.Name))
+	case *goavro.FixedSchema:
+		return exportedName(shortName(s.Name))
+	case *goavro.ArraySchema:
+		return "Array" + memberName(s.Items)
+	case *goavro.MapSchema:
+		return "Map" + memberName(s.Values)
+	default:
+		return "Union"
+	}
+}
+
+// avroName returns the name of the union member used to describe the union.
+func avroName(s goavro.Schema) string {
+	switch s := s.(type) {
+	case *goavro.RecordSchema:
+		return s.Name
+	case *goavro.EnumSchema:
+		return s.Name
+	case *goavro.FixedSchema:
+		return s.Name
+	case *goavro.LogicalSchema:
+		return avroName(s.Underlying)
+	default:
+		return s.Type()
+	}
+}
+
+// writeDoc writes the documentation of a named type as the remainder of its doc comment.
+func writeDoc(w *bytes.Buffer, doc string) {
+	if doc != "" {
+		fmt.Fprintf(w, "\n//\n// %s", strings.Replace(doc, "\n", "\n// ", -1))
+	}
+	w.WriteString("\n")
+}
+
+// shortName returns the name without its namespace.
+func shortName(fullName string) string {
+	return fullName[strings.LastIndexByte(fullName, '.')+1:]
+}
+
+// exportedName converts an Avro name to an exported Go identifier, removing underscores and
+// capitalizing the letter following each of them.
+func exportedName(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			parts = append(parts, strings.ToUpper(part[:1])+part[1:])
+		}
+	}
+	if len(parts) == 0 {
+		return "X"
+	}
+	return strings.Join(parts, "")
+}
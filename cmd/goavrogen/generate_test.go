@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden file with the generated code")
+
+// goldenFile is the file holding the code generated from goldenSchemas, which the example package
+// compiles and tests.
+const goldenFile = "internal/example/example_avro.go"
+
+// goldenSchemas are the schema files from which the code of goldenFile is generated, in order.
+var goldenSchemas = []string{"testdata/user.avsc", "testdata/team.avsc"}
+
+func generateFiles(t *testing.T, pkg string, pathnames ...string) ([]byte, error) {
+	t.Helper()
+	g := newGenerator(pkg)
+	for _, pathname := range pathnames {
+		schema, err := ioutil.ReadFile(pathname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = g.addSchema(string(schema)); err != nil {
+			return nil, err
+		}
+	}
+	return g.source()
+}
+
+func TestGenerateGolden(t *testing.T) {
+	actual, err := generateFiles(t, "example", goldenSchemas...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err = ioutil.WriteFile(goldenFile, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("generated code differs from %s; run go test with -update flag to update it", filepath.FromSlash(goldenFile))
+	}
+}
+
+func TestGenerateCrossFileOrder(t *testing.T) {
+	// NOTE: A schema may only refer to named types defined by the schemas preceding it.
+	_, err := generateFiles(t, "example", "testdata/team.avsc", "testdata/user.avsc")
+	if err == nil || !strings.Contains(err.Error(), `unknown type name: "User"`) {
+		t.Errorf("Actual: %v; Expected: %s", err, "unknown type name")
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	cases := []struct {
+		schema       string
+		errorMessage string
+	}{
+		{`"long"`, "top-level schema ought to be record, enum, or fixed; received: long"},
+		{`{"type":"record","name":"r","fields":[{"name":"a_b","type":"int"},{"name":"aB","type":"int"}]}`, `cannot use Go field name "AB" for both "a_b" and "aB" of record r`},
+		{`{"type":"record","name":"r","fields":[{"name":"a","type":{"type":"record","name":"x.R","fields":[{"name":"b","type":"int"}]}}]}`, `cannot use Go type name "R" for both r and x.R`},
+	}
+	for _, c := range cases {
+		g := newGenerator("example")
+		if err := g.addSchema(c.schema); err == nil || !strings.Contains(err.Error(), c.errorMessage) {
+			t.Errorf("schema: %s; Actual: %v; Expected: %s", c.schema, err, c.errorMessage)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package example holds the code generated by goavrogen from the schema files in the testdata
+// directory of the command, so the generated code is compiled and tested along with the command.
+// The command's tests compare its output with example_avro.go, which may be regenerated by running
+// them with the -update flag.
+package example
+
+//go:generate go run ../.. -package example -o example_avro.go ../../testdata/user.avsc ../../testdata/team.avsc
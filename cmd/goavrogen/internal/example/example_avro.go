@@ -0,0 +1,451 @@
+// Code generated by goavrogen. DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/karrick/goavro"
+)
+
+// Status is the Go representation of the Avro enum com.example.Status.
+type Status string
+
+// Symbols of the Status enum.
+const (
+	StatusACTIVE    Status = "ACTIVE"
+	StatusSUSPENDED Status = "SUSPENDED"
+)
+
+// MarshalAvro appends the binary Avro encoding of the enum to buf.  On error, it returns the
+// original byte slice.
+func (e Status) MarshalAvro(buf []byte) ([]byte, error) {
+	switch e {
+	case StatusACTIVE:
+		return goavro.AppendLong(buf, 0), nil
+	case StatusSUSPENDED:
+		return goavro.AppendLong(buf, 1), nil
+	}
+	return buf, fmt.Errorf("cannot encode Enum %q: value ought to be member of symbols: [ACTIVE SUSPENDED]; %q", "com.example.Status", string(e))
+}
+
+// UnmarshalAvro decodes the binary Avro encoding of the enum from buf, and returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+func (e *Status) UnmarshalAvro(buf []byte) ([]byte, error) {
+	index, b, err := goavro.ReadLong(buf)
+	if err != nil {
+		return buf, err
+	}
+	switch index {
+	case 0:
+		*e = StatusACTIVE
+	case 1:
+		*e = StatusSUSPENDED
+	default:
+		return buf, fmt.Errorf("cannot decode Enum %q: index ought to be between 0 and 1; read index: %d", "com.example.Status", index)
+	}
+	return b, nil
+}
+
+// AvroFullName returns the full name of the Avro enum.
+func (e Status) AvroFullName() string {
+	return "com.example.Status"
+}
+
+// AvroFingerprint returns the Rabin fingerprint of the canonical form of the schema of the
+// Avro enum.
+func (e Status) AvroFingerprint() uint64 {
+	return 0xe45e999d3b67647d
+}
+
+// Hash is the Go representation of the Avro fixed com.example.Hash.
+type Hash [4]byte
+
+// MarshalAvro appends the binary Avro encoding of the fixed to buf.
+func (f Hash) MarshalAvro(buf []byte) ([]byte, error) {
+	return append(buf, f[:]...), nil
+}
+
+// UnmarshalAvro decodes the binary Avro encoding of the fixed from buf, and returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+func (f *Hash) UnmarshalAvro(buf []byte) ([]byte, error) {
+	v, b, err := goavro.ReadFixed(buf, 4)
+	if err != nil {
+		return buf, err
+	}
+	copy(f[:], v)
+	return b, nil
+}
+
+// AvroFullName returns the full name of the Avro fixed.
+func (f Hash) AvroFullName() string {
+	return "com.example.Hash"
+}
+
+// AvroFingerprint returns the Rabin fingerprint of the canonical form of the schema of the
+// Avro fixed.
+func (f Hash) AvroFingerprint() uint64 {
+	return 0xf146bf2a5524737f
+}
+
+// UnionNullStringLong is the Go representation of the Avro union:
+//
+//	[null,string,long]
+//
+// Index holds the position of the member of the union whose field holds the value.
+type UnionNullStringLong struct {
+	Index  int
+	String string
+	Long   int64
+}
+
+// MarshalAvro appends the binary Avro encoding of the union to buf.  On error, it returns the
+// original byte slice.
+func (u *UnionNullStringLong) MarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	switch u.Index {
+	case 0:
+		b = goavro.AppendLong(b, 0)
+	case 1:
+		b = goavro.AppendLong(b, 1)
+		b = goavro.AppendString(b, u.String)
+	case 2:
+		b = goavro.AppendLong(b, 2)
+		b = goavro.AppendLong(b, u.Long)
+	default:
+		return buf, fmt.Errorf("cannot encode Union: index ought to be between 0 and 2; received: %d", u.Index)
+	}
+	return b, nil
+}
+
+// UnmarshalAvro decodes the binary Avro encoding of the union from buf, and returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+func (u *UnionNullStringLong) UnmarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	var index int64
+	if index, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	switch index {
+	case 0:
+	case 1:
+		if u.String, b, err = goavro.ReadString(b); err != nil {
+			return buf, err
+		}
+	case 2:
+		if u.Long, b, err = goavro.ReadLong(b); err != nil {
+			return buf, err
+		}
+	default:
+		return buf, fmt.Errorf("cannot decode Union: index ought to be between 0 and 2; read index: %d", index)
+	}
+	u.Index = int(index)
+	return b, nil
+}
+
+// User is the Go representation of the Avro record com.example.User.
+//
+// A user of the example service.
+type User struct {
+	Id int64 `avro:"id"`
+	// Full name of the user.
+	Name     string              `avro:"name"`
+	Active   bool                `avro:"active"`
+	Age      int32               `avro:"age"`
+	Score    float32             `avro:"score"`
+	Balance  float64             `avro:"balance"`
+	Avatar   []byte              `avro:"avatar"`
+	Email    *string             `avro:"email"`
+	Status   Status              `avro:"status"`
+	Hash     Hash                `avro:"hash"`
+	Tags     []string            `avro:"tags"`
+	Counts   map[string]int64    `avro:"counts"`
+	Birthday time.Time           `avro:"birthday"`
+	Created  time.Time           `avro:"created"`
+	Elapsed  time.Duration       `avro:"elapsed"`
+	Contact  UnionNullStringLong `avro:"contact"`
+	Manager  *User               `avro:"manager"`
+}
+
+// MarshalAvro appends the binary Avro encoding of the record to buf.  On error, it returns the
+// original byte slice.
+func (r *User) MarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	b = goavro.AppendLong(b, r.Id)
+	b = goavro.AppendString(b, r.Name)
+	b = goavro.AppendBoolean(b, r.Active)
+	b = goavro.AppendInt(b, r.Age)
+	b = goavro.AppendFloat(b, r.Score)
+	b = goavro.AppendDouble(b, r.Balance)
+	b = goavro.AppendBytes(b, r.Avatar)
+	if r.Email == nil {
+		b = goavro.AppendLong(b, 0)
+	} else {
+		b = goavro.AppendLong(b, 1)
+		b = goavro.AppendString(b, (*r.Email))
+	}
+	if b, err = r.Status.MarshalAvro(b); err != nil {
+		return buf, err
+	}
+	if b, err = r.Hash.MarshalAvro(b); err != nil {
+		return buf, err
+	}
+	if len(r.Tags) > 0 {
+		b = goavro.AppendLong(b, int64(len(r.Tags)))
+		for _, v3 := range r.Tags {
+			b = goavro.AppendString(b, v3)
+		}
+	}
+	b = goavro.AppendLong(b, 0)
+	if len(r.Counts) > 0 {
+		b = goavro.AppendLong(b, int64(len(r.Counts)))
+		for k6, v7 := range r.Counts {
+			b = goavro.AppendString(b, k6)
+			b = goavro.AppendLong(b, v7)
+		}
+	}
+	b = goavro.AppendLong(b, 0)
+	b = goavro.AppendInt(b, int32(time.Date(r.Birthday.Year(), r.Birthday.Month(), r.Birthday.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400))
+	b = goavro.AppendLong(b, r.Created.UnixMilli())
+	b = goavro.AppendLong(b, int64(r.Elapsed/time.Microsecond))
+	if b, err = r.Contact.MarshalAvro(b); err != nil {
+		return buf, err
+	}
+	if r.Manager == nil {
+		b = goavro.AppendLong(b, 0)
+	} else {
+		b = goavro.AppendLong(b, 1)
+		if b, err = (*r.Manager).MarshalAvro(b); err != nil {
+			return buf, err
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalAvro decodes the binary Avro encoding of the record from buf, and returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+func (r *User) UnmarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	if r.Id, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	if r.Name, b, err = goavro.ReadString(b); err != nil {
+		return buf, err
+	}
+	if r.Active, b, err = goavro.ReadBoolean(b); err != nil {
+		return buf, err
+	}
+	if r.Age, b, err = goavro.ReadInt(b); err != nil {
+		return buf, err
+	}
+	if r.Score, b, err = goavro.ReadFloat(b); err != nil {
+		return buf, err
+	}
+	if r.Balance, b, err = goavro.ReadDouble(b); err != nil {
+		return buf, err
+	}
+	var v1 []byte
+	if v1, b, err = goavro.ReadBytes(b); err != nil {
+		return buf, err
+	}
+	r.Avatar = append([]byte(nil), v1...)
+	var i2 int64
+	if i2, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	switch i2 {
+	case 0:
+		r.Email = nil
+	case 1:
+		r.Email = new(string)
+		if (*r.Email), b, err = goavro.ReadString(b); err != nil {
+			return buf, err
+		}
+	default:
+		return buf, fmt.Errorf("cannot decode Union: index ought to be 0 or 1; read index: %d", i2)
+	}
+	if b, err = r.Status.UnmarshalAvro(b); err != nil {
+		return buf, err
+	}
+	if b, err = r.Hash.UnmarshalAvro(b); err != nil {
+		return buf, err
+	}
+	r.Tags = make([]string, 0)
+	for {
+		var n4 int64
+		if n4, b, err = goavro.ReadBlockCount(b); err != nil {
+			return buf, err
+		}
+		if n4 == 0 {
+			break
+		}
+		for ; n4 > 0; n4-- {
+			var v5 string
+			if v5, b, err = goavro.ReadString(b); err != nil {
+				return buf, err
+			}
+			r.Tags = append(r.Tags, v5)
+		}
+	}
+	r.Counts = make(map[string]int64)
+	for {
+		var n8 int64
+		if n8, b, err = goavro.ReadBlockCount(b); err != nil {
+			return buf, err
+		}
+		if n8 == 0 {
+			break
+		}
+		for ; n8 > 0; n8-- {
+			var k9 string
+			if k9, b, err = goavro.ReadString(b); err != nil {
+				return buf, err
+			}
+			var v10 int64
+			if v10, b, err = goavro.ReadLong(b); err != nil {
+				return buf, err
+			}
+			r.Counts[k9] = v10
+		}
+	}
+	var v11 int32
+	if v11, b, err = goavro.ReadInt(b); err != nil {
+		return buf, err
+	}
+	r.Birthday = time.Unix(int64(v11)*86400, 0).UTC()
+	var v12 int64
+	if v12, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	r.Created = time.UnixMilli(v12).UTC()
+	var v13 int64
+	if v13, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	r.Elapsed = time.Duration(v13) * time.Microsecond
+	if b, err = r.Contact.UnmarshalAvro(b); err != nil {
+		return buf, err
+	}
+	var i14 int64
+	if i14, b, err = goavro.ReadLong(b); err != nil {
+		return buf, err
+	}
+	switch i14 {
+	case 0:
+		r.Manager = nil
+	case 1:
+		r.Manager = new(User)
+		if b, err = (*r.Manager).UnmarshalAvro(b); err != nil {
+			return buf, err
+		}
+	default:
+		return buf, fmt.Errorf("cannot decode Union: index ought to be 0 or 1; read index: %d", i14)
+	}
+	return b, nil
+}
+
+// AvroFullName returns the full name of the Avro record.
+func (r *User) AvroFullName() string {
+	return "com.example.User"
+}
+
+// AvroFingerprint returns the Rabin fingerprint of the canonical form of the schema of the
+// Avro record.
+func (r *User) AvroFingerprint() uint64 {
+	return 0xb7e54e15817e24d5
+}
+
+// UserAvroSchema is the Avro schema of User.
+const UserAvroSchema = `{"type":"record","name":"com.example.User","doc":"A user of the example service.","fields":[{"name":"id","type":"long"},{"name":"name","type":"string","doc":"Full name of the user."},{"name":"active","type":"boolean"},{"name":"age","type":"int"},{"name":"score","type":"float"},{"name":"balance","type":"double"},{"name":"avatar","type":"bytes"},{"name":"email","type":["null","string"]},{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","SUSPENDED"]}},{"name":"hash","type":{"type":"fixed","name":"Hash","size":4}},{"name":"tags","type":{"type":"array","items":"string"}},{"name":"counts","type":{"type":"map","values":"long"}},{"name":"birthday","type":{"type":"int","logicalType":"date"}},{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},{"name":"elapsed","type":{"type":"long","logicalType":"time-micros"}},{"name":"contact","type":["null","string","long"]},{"name":"manager","type":["null","User"]}]}`
+
+// UserAvroFingerprint is the Rabin fingerprint of the canonical form of UserAvroSchema.
+const UserAvroFingerprint uint64 = 0xb7e54e15817e24d5
+
+// Team is the Go representation of the Avro record com.example.Team.
+//
+// A team of users, which are defined by user.avsc.
+type Team struct {
+	Name    string `avro:"name"`
+	Lead    User   `avro:"lead"`
+	Members []User `avro:"members"`
+	Status  Status `avro:"status"`
+}
+
+// MarshalAvro appends the binary Avro encoding of the record to buf.  On error, it returns the
+// original byte slice.
+func (r *Team) MarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	b = goavro.AppendString(b, r.Name)
+	if b, err = r.Lead.MarshalAvro(b); err != nil {
+		return buf, err
+	}
+	if len(r.Members) > 0 {
+		b = goavro.AppendLong(b, int64(len(r.Members)))
+		for _, v15 := range r.Members {
+			if b, err = v15.MarshalAvro(b); err != nil {
+				return buf, err
+			}
+		}
+	}
+	b = goavro.AppendLong(b, 0)
+	if b, err = r.Status.MarshalAvro(b); err != nil {
+		return buf, err
+	}
+	return b, nil
+}
+
+// UnmarshalAvro decodes the binary Avro encoding of the record from buf, and returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice.
+func (r *Team) UnmarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	if r.Name, b, err = goavro.ReadString(b); err != nil {
+		return buf, err
+	}
+	if b, err = r.Lead.UnmarshalAvro(b); err != nil {
+		return buf, err
+	}
+	r.Members = make([]User, 0)
+	for {
+		var n16 int64
+		if n16, b, err = goavro.ReadBlockCount(b); err != nil {
+			return buf, err
+		}
+		if n16 == 0 {
+			break
+		}
+		for ; n16 > 0; n16-- {
+			var v17 User
+			if b, err = v17.UnmarshalAvro(b); err != nil {
+				return buf, err
+			}
+			r.Members = append(r.Members, v17)
+		}
+	}
+	if b, err = r.Status.UnmarshalAvro(b); err != nil {
+		return buf, err
+	}
+	return b, nil
+}
+
+// AvroFullName returns the full name of the Avro record.
+func (r *Team) AvroFullName() string {
+	return "com.example.Team"
+}
+
+// AvroFingerprint returns the Rabin fingerprint of the canonical form of the schema of the
+// Avro record.
+func (r *Team) AvroFingerprint() uint64 {
+	return 0x91d342f48b376705
+}
+
+// TeamAvroSchema is the Avro schema of Team.
+const TeamAvroSchema = `{"type":"record","name":"com.example.Team","doc":"A team of users, which are defined by user.avsc.","fields":[{"name":"name","type":"string"},{"name":"lead","type":{"type":"record","name":"com.example.User","doc":"A user of the example service.","fields":[{"name":"id","type":"long"},{"name":"name","doc":"Full name of the user.","type":"string"},{"name":"active","type":"boolean"},{"name":"age","type":"int"},{"name":"score","type":"float"},{"name":"balance","type":"double"},{"name":"avatar","type":"bytes"},{"name":"email","type":["null","string"]},{"name":"status","type":{"type":"enum","name":"com.example.Status","symbols":["ACTIVE","SUSPENDED"]}},{"name":"hash","type":{"type":"fixed","name":"com.example.Hash","size":4}},{"name":"tags","type":{"type":"array","items":"string"}},{"name":"counts","type":{"type":"map","values":"long"}},{"name":"birthday","type":{"type":"int","logicalType":"date"}},{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},{"name":"elapsed","type":{"type":"long","logicalType":"time-micros"}},{"name":"contact","type":["null","string","long"]},{"name":"manager","type":["null","com.example.User"]}]}},{"name":"members","type":{"type":"array","items":"com.example.User"}},{"name":"status","type":"com.example.Status"}]}`
+
+// TeamAvroFingerprint is the Rabin fingerprint of the canonical form of TeamAvroSchema.
+const TeamAvroFingerprint uint64 = 0x91d342f48b376705
@@ -0,0 +1,175 @@
+package example_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karrick/goavro"
+	"github.com/karrick/goavro/cmd/goavrogen/internal/example"
+)
+
+func exampleUser() example.User {
+	email := "alice@example.com"
+	return example.User{
+		Id:       42,
+		Name:     "alice",
+		Active:   true,
+		Age:      40,
+		Score:    1.5,
+		Balance:  -2.25,
+		Avatar:   []byte("avatar"),
+		Email:    &email,
+		Status:   example.StatusSUSPENDED,
+		Hash:     example.Hash{1, 2, 3, 4},
+		Tags:     []string{"a", "b"},
+		Counts:   map[string]int64{"k": 3},
+		Birthday: time.Date(1980, time.March, 4, 0, 0, 0, 0, time.UTC),
+		Created:  time.Date(2020, time.January, 2, 3, 4, 5, 6e6, time.UTC),
+		Elapsed:  90 * time.Second,
+		Contact:  example.UnionNullStringLong{Index: 2, Long: 5551234},
+		Manager: &example.User{
+			Id:      7,
+			Name:    "carol",
+			Status:  example.StatusACTIVE,
+			Tags:    []string{},
+			Counts:  map[string]int64{},
+			Created: time.Unix(0, 0).UTC(),
+			Contact: example.UnionNullStringLong{Index: 1, String: "desk"},
+		},
+	}
+}
+
+// roundTrip encodes the value with its MarshalAvro method, decodes the encoding with a Codec for
+// the schema, and checks that the Codec encodes the decoded datum to the same bytes, which the
+// UnmarshalAvro method of decoded decodes to the original value.
+func roundTrip(t *testing.T, schema string, value, decoded interface {
+	MarshalAvro([]byte) ([]byte, error)
+	UnmarshalAvro([]byte) ([]byte, error)
+}) interface{} {
+	t.Helper()
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := value.MarshalAvro(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, rest, err := codec.BinaryDecode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", rest, nil)
+	}
+	encoded, err := codec.BinaryEncode(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, buf) {
+		t.Errorf("Actual: %v; Expected: %v", encoded, buf)
+	}
+	if rest, err = decoded.UnmarshalAvro(append(encoded, 0xff)); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, value)
+	}
+	return datum
+}
+
+func TestUserRoundTrip(t *testing.T) {
+	user := exampleUser()
+	datum := roundTrip(t, example.UserAvroSchema, &user, new(example.User)).(map[string]interface{})
+
+	if actual, expected := datum["name"], "alice"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := datum["status"], "SUSPENDED"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := datum["contact"], goavro.Union("long", int64(5551234)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := datum["created"], user.Created; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestTeamRoundTrip(t *testing.T) {
+	// NOTE: Team refers to User, which is defined by another schema file, so TeamAvroSchema ought
+	// to include the definition of User.
+	lead := exampleUser()
+	team := example.Team{
+		Name:    "core",
+		Lead:    lead,
+		Members: []example.User{*lead.Manager},
+		Status:  example.StatusACTIVE,
+	}
+	roundTrip(t, example.TeamAvroSchema, &team, new(example.Team))
+}
+
+func TestFingerprint(t *testing.T) {
+	for schema, expected := range map[string]uint64{
+		example.UserAvroSchema: example.UserAvroFingerprint,
+		example.TeamAvroSchema: example.TeamAvroFingerprint,
+	} {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := codec.Fingerprint(); actual != expected {
+			t.Errorf("Actual: %#x; Expected: %#x", actual, expected)
+		}
+	}
+}
+
+func TestMarshalerWithCodec(t *testing.T) {
+	// NOTE: Codec.Marshal and Codec.Unmarshal use the methods of the generated types.
+	codec, err := goavro.NewCodec(example.UserAvroSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := exampleUser()
+	buf, err := codec.Marshal(nil, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := user.MarshalAvro(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %v; Expected: %v", buf, expected)
+	}
+	var decoded example.User
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, user) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, user)
+	}
+}
+
+func TestMarshalerWithMismatchedCodec(t *testing.T) {
+	// NOTE: Generated types only encode themselves for the named type they were generated for, so
+	// these schemas are encoded using reflection, which rejects a Hash of another size.
+	for _, schema := range []string{
+		`{"type":"fixed","name":"other","size":16}`,
+		`{"type":"fixed","name":"com.example.Hash","size":16}`,
+	} {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = codec.Marshal(nil, example.Hash{1, 2, 3, 4}); err == nil || !strings.Contains(err.Error(), "cannot use Go type example.Hash") {
+			t.Errorf("schema: %s; Actual: %v; Expected: %s", schema, err, "cannot use Go type example.Hash")
+		}
+	}
+}
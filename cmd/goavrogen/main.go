@@ -0,0 +1,84 @@
+// Command goavrogen generates Go types from Avro schema files, with MarshalAvro and UnmarshalAvro
+// methods that encode and decode the binary Avro encoding without boxing values in interface{}.
+//
+// Each schema file ought to contain a named type, usually a record.  For each named type it
+// defines, goavrogen generates a struct for a record, a string type with a constant for each
+// symbol of an enum, and a byte array type for a fixed.  Unions of null and one other type are
+// represented by pointers, and other unions by generated wrapper structs.  The date, time, and
+// timestamp logical types are represented by time.Time and time.Duration, and other logical types
+// by their underlying types.  Each top-level type also gets constants holding its schema and the
+// Rabin fingerprint of the schema's canonical form.  The types of named types have AvroFullName and
+// AvroFingerprint methods, so Codec.Marshal and Codec.Unmarshal only use their MarshalAvro and
+// UnmarshalAvro methods for the named type they were generated from.
+//
+// A schema file may refer to the named types defined by the schema files preceding it on the
+// command line.  The schema constant of such a file includes the definitions of those types, so
+// the constant may be used by itself.
+//
+// It may be used with go:generate:
+//
+//	//go:generate goavrogen -o user_avro.go user.avsc
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func usage() {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+	base := filepath.Base(executable)
+	fmt.Fprintf(os.Stderr, "Usage of %s:\n", base)
+	fmt.Fprintf(os.Stderr, "\t%s [-package name] [-o output.go] schema.avsc [schema2.avsc ...]\n", base)
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "name of the package of the generated code (default: $GOPACKAGE, as set by go generate)")
+	output := flag.String("o", "", "name of the file to write (default: standard output)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if len(flag.Args()) == 0 {
+		usage()
+	}
+	if *pkg == "" {
+		bail(fmt.Errorf("cannot generate code without package name: use -package flag"))
+	}
+
+	g := newGenerator(*pkg)
+	for _, pathname := range flag.Args() {
+		schema, err := ioutil.ReadFile(pathname)
+		if err != nil {
+			bail(err)
+		}
+		if err = g.addSchema(string(schema)); err != nil {
+			bail(fmt.Errorf("%s: %s", pathname, err))
+		}
+	}
+
+	source, err := g.source()
+	if err != nil {
+		bail(err)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+	} else {
+		err = ioutil.WriteFile(*output, source, 0644)
+	}
+	if err != nil {
+		bail(err)
+	}
+}
+
+func bail(err error) {
+	fmt.Fprintf(os.Stderr, "%s\n", err)
+	os.Exit(1)
+}
@@ -3,6 +3,7 @@ package goavro
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // BinaryDecoder interface describes types that expose the Decode method.
@@ -21,33 +22,83 @@ type BinaryCoder interface {
 	BinaryEncoder
 }
 
+// TextDecoder interface describes types that expose the TextDecode method.
+type TextDecoder interface {
+	TextDecode([]byte) (interface{}, []byte, error)
+}
+
+// TextEncoder interface describes types that expose the TextEncode method.
+type TextEncoder interface {
+	TextEncode([]byte, interface{}) ([]byte, error)
+}
+
+// TextCoder interface describes types that expose both the TextDecode and the TextEncode methods.
+type TextCoder interface {
+	TextDecoder
+	TextEncoder
+}
+
 // Codec stores function pointers for encoding and decoding Avro blobs according to their defined
 // specification.  Their state is created during initialization, but then never modified, so the
 // same Codec may be safely used in multiple go routines to encode and or decode different Avro
 // streams concurrently.
 type Codec struct {
 	typeName    *name
+	aliases     []string // full names of aliases of a named type
+	doc         string   // documentation of a named type
 	symbolTable map[string]*Codec
 
 	binaryDecoder func([]byte) (interface{}, []byte, error)
 	binaryEncoder func([]byte, interface{}) ([]byte, error)
 
-	// textDecoder func([]byte) (interface{}, []byte, error)
-	// textEncoder func([]byte, interface{}) ([]byte, error)
+	// binarySkipper returns the byte slice following one binary encoded value, without decoding
+	// the value, and without allocating.
+	binarySkipper func([]byte) ([]byte, error)
+
+	textDecoder func([]byte) (interface{}, []byte, error)
+	textEncoder func([]byte, interface{}) ([]byte, error)
+
+	// defaultDecoder converts a default value, as unmarshaled from the JSON schema, to the native
+	// Go value of the type.
+	defaultDecoder func(interface{}) (interface{}, error)
+
+	// NOTE: The following fields describe the structure of the schema from which the codec was
+	// built, for use by operations that walk the schema, such as schema resolution.
+	kind          string         // Avro type, such as "int", "array", "enum", or "record"
+	items         *Codec         // codec for array items or map values
+	members       []*Codec       // codecs for union members, in schema order
+	fields        []*recordField // record fields, in schema order
+	symbols       []string       // enum symbols
+	symbolDefault string         // enum symbol used when resolving an unknown symbol, if any
+	size          int            // fixed size
+
+	logicalType       string                                 // logical type annotating the underlying type, if any
+	logicalProperties map[string]interface{}                 // attributes of the logical type, such as precision and scale
+	underlying        *Codec                                 // codec for the underlying type of a logical type
+	toNative          func(interface{}) (interface{}, error) // converts underlying values to logical type values
+
+	// reader is the codec for the reader schema, and writer the codec for the writer schema, when
+	// binaryDecoder decodes data written with another schema, and both are nil otherwise.
+	// Operations that follow the structure of the schema, such as encoding, use the reader codec.
+	reader *Codec
+	writer *Codec
+
+	// plans caches the plan for each Go type used with Marshal and Unmarshal.
+	plans *sync.Map
 }
 
 // NewCodec returns a Codec that can encode and decode the specified Avro schema.
 func NewCodec(schemaSpecification string) (*Codec, error) {
 	// bootstrap a symbol table with primitive type codecs for the new codec
 	st := map[string]*Codec{
-		"boolean": &Codec{typeName: &name{"boolean", nullNamespace}, binaryDecoder: booleanDecoder, binaryEncoder: booleanEncoder},
-		"bytes":   &Codec{typeName: &name{"bytes", nullNamespace}, binaryDecoder: bytesDecoder, binaryEncoder: bytesEncoder},
-		"double":  &Codec{typeName: &name{"double", nullNamespace}, binaryDecoder: doubleDecoder, binaryEncoder: doubleEncoder},
-		"float":   &Codec{typeName: &name{"float", nullNamespace}, binaryDecoder: floatDecoder, binaryEncoder: floatEncoder},
-		"int":     &Codec{typeName: &name{"int", nullNamespace}, binaryDecoder: intDecoder, binaryEncoder: intEncoder},
-		"long":    &Codec{typeName: &name{"long", nullNamespace}, binaryDecoder: longDecoder, binaryEncoder: longEncoder},
-		"null":    &Codec{typeName: &name{"null", nullNamespace}, binaryDecoder: nullDecoder, binaryEncoder: nullEncoder},
-		"string":  &Codec{typeName: &name{"string", nullNamespace}, binaryDecoder: stringDecoder, binaryEncoder: stringEncoder},
+		"boolean": &Codec{typeName: &name{"boolean", nullNamespace}, kind: "boolean", binaryDecoder: booleanDecoder, binaryEncoder: booleanEncoder, binarySkipper: booleanSkipper, textDecoder: booleanTextDecoder, textEncoder: booleanTextEncoder, defaultDecoder: booleanDefaultDecoder},
+		"bytes":   &Codec{typeName: &name{"bytes", nullNamespace}, kind: "bytes", binaryDecoder: bytesDecoder, binaryEncoder: bytesEncoder, binarySkipper: bytesSkipper, textDecoder: bytesTextDecoder, textEncoder: bytesTextEncoder, defaultDecoder: bytesDefaultDecoder},
+		"double":  &Codec{typeName: &name{"double", nullNamespace}, kind: "double", binaryDecoder: doubleDecoder, binaryEncoder: doubleEncoder, binarySkipper: doubleSkipper, textDecoder: doubleTextDecoder, textEncoder: doubleTextEncoder, defaultDecoder: doubleDefaultDecoder},
+		"float":   &Codec{typeName: &name{"float", nullNamespace}, kind: "float", binaryDecoder: floatDecoder, binaryEncoder: floatEncoder, binarySkipper: floatSkipper, textDecoder: floatTextDecoder, textEncoder: floatTextEncoder, defaultDecoder: floatDefaultDecoder},
+		"int":     &Codec{typeName: &name{"int", nullNamespace}, kind: "int", binaryDecoder: intDecoder, binaryEncoder: intEncoder, binarySkipper: longSkipper, textDecoder: intTextDecoder, textEncoder: intTextEncoder, defaultDecoder: intDefaultDecoder},
+		"long":    &Codec{typeName: &name{"long", nullNamespace}, kind: "long", binaryDecoder: longDecoder, binaryEncoder: longEncoder, binarySkipper: longSkipper, textDecoder: longTextDecoder, textEncoder: longTextEncoder, defaultDecoder: longDefaultDecoder},
+		"null":    &Codec{typeName: &name{"null", nullNamespace}, kind: "null", binaryDecoder: nullDecoder, binaryEncoder: nullEncoder, binarySkipper: nullSkipper, textDecoder: nullTextDecoder, textEncoder: nullTextEncoder, defaultDecoder: nullDefaultDecoder},
+		"string":  &Codec{typeName: &name{"string", nullNamespace}, kind: "string", binaryDecoder: stringDecoder, binaryEncoder: stringEncoder, binarySkipper: stringSkipper, textDecoder: stringTextDecoder, textEncoder: stringTextEncoder, defaultDecoder: stringDefaultDecoder},
 	}
 
 	// NOTE: Some clients might give us unadorned primitive type name for the schema, e.g., "long".
@@ -55,6 +106,7 @@ func NewCodec(schemaSpecification string) (*Codec, error) {
 	// type names.
 	if c, ok := st[schemaSpecification]; ok {
 		c.symbolTable = st
+		c.plans = new(sync.Map)
 		return c, nil
 	}
 
@@ -67,6 +119,7 @@ func NewCodec(schemaSpecification string) (*Codec, error) {
 	c, err := buildCodec(st, nullNamespace, schema)
 	if err == nil {
 		c.symbolTable = st
+		c.plans = new(sync.Map)
 	}
 	return c, err
 }
@@ -95,6 +148,30 @@ func (c Codec) BinaryEncode(buf []byte, datum interface{}) ([]byte, error) {
 	return newBuf, nil
 }
 
+// TextDecode decodes the provided byte slice in accordance with the Codec's Avro schema, using the
+// JSON encoding described in the Avro specification.  On success, it returns the decoded value,
+// along with a new byte slice with the decoded bytes consumed.  On error, it returns the original
+// byte slice without any bytes consumed and the error.
+func (c Codec) TextDecode(buf []byte) (interface{}, []byte, error) {
+	value, newBuf, err := c.textDecoder(buf)
+	if err != nil {
+		return nil, buf, err // if error, return original byte slice
+	}
+	return value, newBuf, nil
+}
+
+// TextEncode encodes the provided datum value in accordance with the Codec's Avro schema, using
+// the JSON encoding described in the Avro specification.  It takes a byte slice to which to append
+// the encoded bytes.  On success, it returns the new byte slice with the appended byte slice.  On
+// error, it returns the original byte slice without any encoded bytes.
+func (c Codec) TextEncode(buf []byte, datum interface{}) ([]byte, error) {
+	newBuf, err := c.textEncoder(buf, datum)
+	if err != nil {
+		return buf, err // if error, return original byte slice
+	}
+	return newBuf, nil
+}
+
 // convert a schema data structure to a codec, prefixing with specified namespace
 func buildCodec(st map[string]*Codec, enclosingNamespace string, schema interface{}) (*Codec, error) {
 	switch schemaType := schema.(type) {
@@ -124,7 +201,12 @@ func buildCodecForTypeDescribedByMap(st map[string]*Codec, enclosingNamespace st
 		// EXAMPLE: "type":"int"
 		// EXAMPLE: "type":"record"
 		// EXAMPLE: "type":"somePreviouslyDefinedCustomTypeString"
-		return buildCodecForTypeDescribedByString(st, enclosingNamespace, v, schemaMap)
+		c, err := buildCodecForTypeDescribedByString(st, enclosingNamespace, v, schemaMap)
+		if err != nil {
+			return nil, err
+		}
+		// EXAMPLE: "type":"bytes","logicalType":"decimal","precision":4,"scale":2
+		return buildCodecForLogicalType(v, c, schemaMap)
 	case map[string]interface{}:
 		return buildCodecForTypeDescribedByMap(st, enclosingNamespace, v)
 	case []interface{}:
@@ -169,11 +251,34 @@ func registerNewCodec(st map[string]*Codec, schemaMap map[string]interface{}, en
 	if err != nil {
 		return nil, err
 	}
-	c := &Codec{typeName: n}
+	aliases, err := newAliasesFromSchemaMap(n, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	c := &Codec{typeName: n, aliases: aliases}
+	c.doc, _ = schemaMap["doc"].(string)
 	st[n.fullName] = c
+	// NOTE: Aliases are registered in the symbol table, so the named type may be referred to by
+	// any of its aliases.
+	for _, alias := range aliases {
+		if _, ok := st[alias]; ok {
+			return nil, fmt.Errorf("schema alias ought to be unique name: %q", alias)
+		}
+		st[alias] = c
+	}
 	return c, nil
 }
 
+// decodeDefault returns the native Go value for the default value of the codec's type.
+func decodeDefault(c *Codec, datum interface{}) (interface{}, error) {
+	if c.defaultDecoder == nil {
+		// NOTE: Codecs for recursive types are registered in the symbol table before they are
+		// complete.
+		return nil, fmt.Errorf("cannot decode default value for %q before its definition is complete", c.typeName)
+	}
+	return c.defaultDecoder(datum)
+}
+
 func typeNames(st map[string]*Codec) []string {
 	var keys []string
 	for k := range st {
@@ -0,0 +1,191 @@
+package goavro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	confluentMagic      = 0
+	confluentHeaderSize = 5 // magic byte followed by 4-byte big-endian schema ID
+)
+
+// SubjectNameStrategy returns the registry subject under which the schema of the provided Codec is
+// registered, for data sent to the specified topic.  The isKey argument is true when the data is
+// a message key, and false when the data is a message value.
+type SubjectNameStrategy func(topic string, codec *Codec, isKey bool) (string, error)
+
+// TopicNameStrategy returns the topic name followed by "-key" or "-value".  This is the default
+// strategy of the Confluent serializers.
+func TopicNameStrategy(topic string, _ *Codec, isKey bool) (string, error) {
+	if topic == "" {
+		return "", errors.New("cannot use TopicNameStrategy without topic")
+	}
+	if isKey {
+		return topic + "-key", nil
+	}
+	return topic + "-value", nil
+}
+
+// RecordNameStrategy returns the full name of the record described by the Codec's schema.
+func RecordNameStrategy(_ string, codec *Codec, _ bool) (string, error) {
+	if codec.kind != "record" {
+		return "", fmt.Errorf("cannot use RecordNameStrategy with schema type: %q", codec.typeName)
+	}
+	return codec.typeName.fullName, nil
+}
+
+// TopicRecordNameStrategy returns the topic name followed by a hyphen and the full name of the
+// record described by the Codec's schema.
+func TopicRecordNameStrategy(topic string, codec *Codec, isKey bool) (string, error) {
+	if topic == "" {
+		return "", errors.New("cannot use TopicRecordNameStrategy without topic")
+	}
+	recordName, err := RecordNameStrategy(topic, codec, isKey)
+	if err != nil {
+		return "", err
+	}
+	return topic + "-" + recordName, nil
+}
+
+// ConfluentSerializerConfig is used to specify creation parameters for ConfluentSerializer.
+type ConfluentSerializerConfig struct {
+	Registry            SchemaRegistry      // Registry specifies where the schema is registered, (required).
+	Schema              string              // Schema specifies the Avro schema for the data to be encoded, (required).
+	Topic               string              // Topic specifies the topic to which data is sent, (required by topic strategies).
+	IsKey               bool                // IsKey specifies whether data is a message key rather than a message value, (optional).
+	SubjectNameStrategy SubjectNameStrategy // SubjectNameStrategy specifies the registry subject, (optional). If omitted, defaults to TopicNameStrategy.
+}
+
+// ConfluentSerializer encodes data using the Confluent wire format: the magic byte 0, followed by
+// the 4-byte big-endian ID of the schema in the registry, followed by the Avro binary encoding of
+// the data.
+type ConfluentSerializer struct {
+	codec  *Codec
+	header []byte
+}
+
+// NewConfluentSerializer registers the schema with the registry, and returns a ConfluentSerializer
+// that encodes data using the schema.
+func NewConfluentSerializer(config ConfluentSerializerConfig) (*ConfluentSerializer, error) {
+	if config.Registry == nil {
+		return nil, errors.New("cannot create ConfluentSerializer without Registry")
+	}
+	if config.Schema == "" {
+		return nil, errors.New("cannot create ConfluentSerializer without Schema")
+	}
+	strategy := config.SubjectNameStrategy
+	if strategy == nil {
+		strategy = TopicNameStrategy
+	}
+
+	codec, err := NewCodec(config.Schema)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := strategy(config.Topic, codec, config.IsKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ConfluentSerializer: %s", err)
+	}
+	id, err := config.Registry.Register(subject, config.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot register schema for subject %q: %s", subject, err)
+	}
+
+	header := make([]byte, confluentHeaderSize)
+	header[0] = confluentMagic
+	binary.BigEndian.PutUint32(header[1:], uint32(id))
+	return &ConfluentSerializer{codec: codec, header: header}, nil
+}
+
+// ID returns the registry ID of the schema used to encode data.
+func (cs *ConfluentSerializer) ID() int {
+	return int(binary.BigEndian.Uint32(cs.header[1:]))
+}
+
+// Serialize appends the Confluent wire format encoding of the provided datum to buf.  On success,
+// it returns the new byte slice with the appended bytes.  On error, it returns the original byte
+// slice without any encoded bytes.
+func (cs *ConfluentSerializer) Serialize(buf []byte, datum interface{}) ([]byte, error) {
+	newBuf, err := cs.codec.BinaryEncode(append(buf, cs.header...), datum)
+	if err != nil {
+		return buf, err
+	}
+	return newBuf, nil
+}
+
+// ConfluentDeserializer decodes data encoded using the Confluent wire format.  It fetches the
+// schema for each ID from the registry the first time the ID is seen, and stores the Codec for
+// subsequent data.
+type ConfluentDeserializer struct {
+	registry SchemaRegistry
+
+	lock   sync.RWMutex
+	codecs map[int]*Codec
+}
+
+// NewConfluentDeserializer returns a ConfluentDeserializer that fetches schemas from the provided
+// registry.
+func NewConfluentDeserializer(registry SchemaRegistry) (*ConfluentDeserializer, error) {
+	if registry == nil {
+		return nil, errors.New("cannot create ConfluentDeserializer without SchemaRegistry")
+	}
+	return &ConfluentDeserializer{registry: registry, codecs: make(map[int]*Codec)}, nil
+}
+
+// Deserialize decodes Confluent wire format data from the provided byte slice.  On success, it
+// returns the decoded value, along with a new byte slice with the decoded bytes consumed.  On
+// error, it returns the original byte slice without any bytes consumed and the error.
+func (cd *ConfluentDeserializer) Deserialize(buf []byte) (interface{}, []byte, error) {
+	id, err := ConfluentSchemaID(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	c, err := cd.codecForID(id)
+	if err != nil {
+		return nil, buf, fmt.Errorf("cannot decode Confluent wire format: %s", err)
+	}
+	value, newBuf, err := c.BinaryDecode(buf[confluentHeaderSize:])
+	if err != nil {
+		return nil, buf, err
+	}
+	return value, newBuf, nil
+}
+
+// codecForID returns the codec for the schema having the specified ID, fetching the schema from
+// the registry the first time the ID is seen.
+func (cd *ConfluentDeserializer) codecForID(id int) (*Codec, error) {
+	cd.lock.RLock()
+	c, ok := cd.codecs[id]
+	cd.lock.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	schema, err := cd.registry.SchemaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c, err = NewCodec(schema); err != nil {
+		return nil, fmt.Errorf("cannot create codec for schema ID %d: %s", id, err)
+	}
+
+	cd.lock.Lock()
+	cd.codecs[id] = c
+	cd.lock.Unlock()
+	return c, nil
+}
+
+// ConfluentSchemaID returns the registry schema ID of the Confluent wire format data in the
+// provided byte slice.
+func ConfluentSchemaID(buf []byte) (int, error) {
+	if len(buf) < confluentHeaderSize {
+		return 0, fmt.Errorf("cannot decode Confluent wire format: buffer ought to have at least %d bytes; received: %d", confluentHeaderSize, len(buf))
+	}
+	if buf[0] != confluentMagic {
+		return 0, fmt.Errorf("cannot decode Confluent wire format: magic byte ought to be %d; received: %d", confluentMagic, buf[0])
+	}
+	return int(binary.BigEndian.Uint32(buf[1:])), nil
+}
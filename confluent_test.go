@@ -0,0 +1,140 @@
+package goavro_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestSubjectNameStrategies(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"record","name":"com.example.r1","fields":[{"name":"a","type":"int"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		strategy goavro.SubjectNameStrategy
+		isKey    bool
+		expected string
+	}{
+		{goavro.TopicNameStrategy, false, "t1-value"},
+		{goavro.TopicNameStrategy, true, "t1-key"},
+		{goavro.RecordNameStrategy, false, "com.example.r1"},
+		{goavro.TopicRecordNameStrategy, true, "t1-com.example.r1"},
+	}
+	for _, tc := range cases {
+		actual, err := tc.strategy("t1", c, tc.isKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != tc.expected {
+			t.Errorf("Actual: %v; Expected: %v", actual, tc.expected)
+		}
+	}
+
+	c, err = goavro.NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = goavro.RecordNameStrategy("t1", c, false); err == nil || !strings.Contains(err.Error(), "cannot use RecordNameStrategy") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot use RecordNameStrategy")
+	}
+	if _, err = goavro.TopicNameStrategy("", c, false); err == nil || !strings.Contains(err.Error(), "without topic") {
+		t.Errorf("Actual: %v; Expected: %s", err, "without topic")
+	}
+}
+
+func TestConfluentSerializerConfig(t *testing.T) {
+	msr := goavro.NewMemorySchemaRegistry()
+	cases := []struct {
+		config       goavro.ConfluentSerializerConfig
+		errorMessage string
+	}{
+		{goavro.ConfluentSerializerConfig{Schema: `"int"`, Topic: "t1"}, "without Registry"},
+		{goavro.ConfluentSerializerConfig{Registry: msr, Topic: "t1"}, "without Schema"},
+		{goavro.ConfluentSerializerConfig{Registry: msr, Schema: `"integer"`, Topic: "t1"}, "unknown type name"},
+		{goavro.ConfluentSerializerConfig{Registry: msr, Schema: `"int"`}, "without topic"},
+	}
+	for _, tc := range cases {
+		_, err := goavro.NewConfluentSerializer(tc.config)
+		if err == nil || !strings.Contains(err.Error(), tc.errorMessage) {
+			t.Errorf("Actual: %v; Expected: %s", err, tc.errorMessage)
+		}
+	}
+}
+
+func TestConfluentRoundTrip(t *testing.T) {
+	msr := goavro.NewMemorySchemaRegistry()
+	if _, err := msr.Register("other", `"string"`); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := goavro.NewConfluentSerializer(goavro.ConfluentSerializerConfig{
+		Registry:            msr,
+		Schema:              `{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`,
+		Topic:               "t1",
+		SubjectNameStrategy: goavro.TopicRecordNameStrategy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := cs.ID(), 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", msr.Versions("t1-r1")), "[2]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	buf, err := cs.Serialize(nil, map[string]interface{}{"a": int32(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0, 0, 0, 0, 2, 6}; !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+
+	cd, err := goavro.NewConfluentDeserializer(msr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ { // second time uses stored codec
+		value, rest, err := cd.Deserialize(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := fmt.Sprintf("%v", value), "map[a:3]"; actual != expected {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+		if len(rest) != 0 {
+			t.Errorf("Actual: %v; Expected: %v", rest, nil)
+		}
+	}
+}
+
+func TestConfluentDeserializeFail(t *testing.T) {
+	cd, err := goavro.NewConfluentDeserializer(goavro.NewMemorySchemaRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		buf          []byte
+		errorMessage string
+	}{
+		{[]byte{0, 0, 0}, "buffer ought to have at least 5 bytes"},
+		{[]byte{1, 0, 0, 0, 1}, "magic byte ought to be 0"},
+		{[]byte{0, 0, 0, 0, 1}, "cannot find schema with ID: 1"},
+	}
+	for _, tc := range cases {
+		value, buf, err := cd.Deserialize(tc.buf)
+		if err == nil || !strings.Contains(err.Error(), tc.errorMessage) {
+			t.Errorf("Actual: %v; Expected: %s", err, tc.errorMessage)
+		}
+		if value != nil {
+			t.Errorf("Actual: %v; Expected: %v", value, nil)
+		}
+		if !bytes.Equal(buf, tc.buf) {
+			t.Errorf("Actual: %v; Expected: %v", buf, tc.buf)
+		}
+	}
+}
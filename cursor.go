@@ -1,16 +1,226 @@
 package goavro
 
-type cursor []byte
+import "fmt"
 
-func (f *cursor) DecodeBoolean() (v bool, e error) {
-	var i interface{}
-	if i, *f, e = booleanDecoder(*f); e == nil {
-		v = i.(bool)
+// Cursor reads and writes the binary encoding of individual Avro values using Go types rather
+// than interface{}, so hand-written code may encode and decode data without boxing each value.
+// Decode methods consume the encoded bytes from the start of the Cursor, and Encode methods append
+// encoded bytes to its end.  When a Decode method returns an error, the Cursor is left unchanged.
+//
+// Each method is a thin wrapper around the Read or Append function for the same Avro type, such as
+// ReadLong and AppendLong, which remain the canonical API.  DecodeUnionIndex also checks the index
+// against the number of union members, and DecodeBlocks and EncodeBlocks loop over the blocks of
+// arrays and maps.
+//
+//	f := goavro.Cursor(buf)
+//	name, err := f.DecodeString()
+//	if err != nil {
+//	    return err
+//	}
+//	age, err := f.DecodeInt()
+//
+// A Cursor may be converted from and to a byte slice, and has no other state.
+type Cursor []byte
+
+// DecodeNull consumes the binary encoding of an Avro null, which has no bytes.
+func (f *Cursor) DecodeNull() error { return nil }
+
+// EncodeNull appends the binary encoding of an Avro null, which has no bytes.
+func (f *Cursor) EncodeNull() error { return nil }
+
+// DecodeBoolean consumes the binary encoding of an Avro boolean.
+func (f *Cursor) DecodeBoolean() (v bool, e error) {
+	v, *f, e = ReadBoolean(*f)
+	return
+}
+
+// EncodeBoolean appends the binary encoding of an Avro boolean.
+func (f *Cursor) EncodeBoolean(v bool) error {
+	*f = AppendBoolean(*f, v)
+	return nil
+}
+
+// DecodeInt consumes the binary encoding of an Avro int.
+func (f *Cursor) DecodeInt() (v int32, e error) {
+	v, *f, e = ReadInt(*f)
+	return
+}
+
+// EncodeInt appends the binary encoding of an Avro int.
+func (f *Cursor) EncodeInt(v int32) error {
+	*f = AppendInt(*f, v)
+	return nil
+}
+
+// DecodeLong consumes the binary encoding of an Avro long.
+func (f *Cursor) DecodeLong() (v int64, e error) {
+	v, *f, e = ReadLong(*f)
+	return
+}
+
+// EncodeLong appends the binary encoding of an Avro long.
+func (f *Cursor) EncodeLong(v int64) error {
+	*f = AppendLong(*f, v)
+	return nil
+}
+
+// DecodeFloat consumes the binary encoding of an Avro float.
+func (f *Cursor) DecodeFloat() (v float32, e error) {
+	v, *f, e = ReadFloat(*f)
+	return
+}
+
+// EncodeFloat appends the binary encoding of an Avro float.
+func (f *Cursor) EncodeFloat(v float32) error {
+	*f = AppendFloat(*f, v)
+	return nil
+}
+
+// DecodeDouble consumes the binary encoding of an Avro double.
+func (f *Cursor) DecodeDouble() (v float64, e error) {
+	v, *f, e = ReadDouble(*f)
+	return
+}
+
+// EncodeDouble appends the binary encoding of an Avro double.
+func (f *Cursor) EncodeDouble(v float64) error {
+	*f = AppendDouble(*f, v)
+	return nil
+}
+
+// DecodeBytes consumes the binary encoding of Avro bytes.  The returned slice refers to the bytes
+// of the Cursor, so callers that keep it after modifying those bytes ought to copy it.
+func (f *Cursor) DecodeBytes() (v []byte, e error) {
+	v, *f, e = ReadBytes(*f)
+	return
+}
+
+// EncodeBytes appends the binary encoding of Avro bytes.
+func (f *Cursor) EncodeBytes(v []byte) error {
+	*f = AppendBytes(*f, v)
+	return nil
+}
+
+// DecodeString consumes the binary encoding of an Avro string.
+func (f *Cursor) DecodeString() (v string, e error) {
+	v, *f, e = ReadString(*f)
+	return
+}
+
+// EncodeString appends the binary encoding of an Avro string.
+func (f *Cursor) EncodeString(v string) error {
+	*f = AppendString(*f, v)
+	return nil
+}
+
+// DecodeFixed consumes the binary encoding of an Avro fixed of the specified size.  The returned
+// slice refers to the bytes of the Cursor, so callers that keep it after modifying those bytes
+// ought to copy it.
+func (f *Cursor) DecodeFixed(size int) (v []byte, e error) {
+	v, *f, e = ReadFixed(*f, size)
+	return
+}
+
+// EncodeFixed appends the binary encoding of an Avro fixed of the specified size, which is the
+// value itself.  It returns an error when the value is not of the specified size.
+func (f *Cursor) EncodeFixed(v []byte, size int) error {
+	if len(v) != size {
+		return fmt.Errorf("cannot encode fixed: expected: %d bytes; received: %d", size, len(v))
+	}
+	*f = append(*f, v...)
+	return nil
+}
+
+// DecodeUnionIndex consumes the index of the member of an Avro union used to encode the value that
+// follows it, and returns an error unless the index is less than the number of members.
+func (f *Cursor) DecodeUnionIndex(memberCount int) (int, error) {
+	index, rest, err := ReadLong(*f)
+	if err != nil {
+		return 0, fmt.Errorf("cannot decode union index: %s", err)
 	}
+	if index < 0 || index >= int64(memberCount) {
+		return 0, fmt.Errorf("cannot decode union index: index ought to be between 0 and %d; read index: %d", memberCount-1, index)
+	}
+	*f = rest
+	return int(index), nil
+}
+
+// EncodeUnionIndex appends the index of the member of an Avro union used to encode the value that
+// follows it.
+func (f *Cursor) EncodeUnionIndex(index int) error {
+	*f = AppendLong(*f, int64(index))
+	return nil
+}
+
+// DecodeBlockCount consumes the count of items in the next block of an Avro array or map, along
+// with the block size that follows a negative count.  A count of zero marks the end of the array
+// or map.
+func (f *Cursor) DecodeBlockCount() (v int64, e error) {
+	v, *f, e = ReadBlockCount(*f)
 	return
 }
 
-func (f *cursor) EncodeBoolean(v bool) error {
-	*f, _ = booleanEncoder(*f, v) // only fails for bad type
+// EncodeBlockCount appends the count of items in the next block of an Avro array or map.  Encode a
+// count of zero after the last block to mark the end of the array or map.
+func (f *Cursor) EncodeBlockCount(count int64) error {
+	*f = AppendLong(*f, count)
+	return nil
+}
+
+// DecodeBlocks consumes the blocks of an Avro array or map, calling fn once for each item, which
+// ought to decode the item from the Cursor: the item of an array, or the key and value of a map.
+// It stops at the first error, which it returns.
+//
+//	var names []string
+//	err := f.DecodeBlocks(func() error {
+//	    name, err := f.DecodeString()
+//	    names = append(names, name)
+//	    return err
+//	})
+//
+// Unlike the other Decode methods, the Cursor is left after the last item decoded when fn returns
+// an error.
+func (f *Cursor) DecodeBlocks(fn func() error) error {
+	for {
+		count, err := f.DecodeBlockCount()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		for ; count > 0; count-- {
+			if err = fn(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// EncodeBlocks appends an Avro array or map of count items as a single block, calling fn with the
+// index of each item, which ought to encode the item to the Cursor: the item of an array, or the
+// key and value of a map.  It stops at the first error, which it returns.
+func (f *Cursor) EncodeBlocks(count int, fn func(i int) error) error {
+	if count > 0 {
+		*f = AppendLong(*f, int64(count))
+		for i := 0; i < count; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+	}
+	*f = AppendLong(*f, 0)
+	return nil
+}
+
+// Skip consumes the binary encoding of one value of the Codec's schema without decoding it, and
+// without allocating.  Blocks of arrays and maps preceded by their size in bytes are skipped
+// without skipping their items one by one.
+func (f *Cursor) Skip(c *Codec) error {
+	rest, err := c.binarySkipper(*f)
+	if err != nil {
+		return err
+	}
+	*f = rest
 	return nil
 }
@@ -0,0 +1,210 @@
+package goavro_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const cursorSchema = `{"type":"record","name":"r","fields":[
+	{"name":"n","type":"null"},
+	{"name":"b","type":"boolean"},
+	{"name":"i","type":"int"},
+	{"name":"l","type":"long"},
+	{"name":"f","type":"float"},
+	{"name":"d","type":"double"},
+	{"name":"by","type":"bytes"},
+	{"name":"s","type":"string"},
+	{"name":"fx","type":{"type":"fixed","name":"fx","size":2}},
+	{"name":"a","type":{"type":"array","items":"int"}},
+	{"name":"m","type":{"type":"map","values":"long"}},
+	{"name":"u","type":["null","string"]}]}`
+
+func TestCursorEncodeMatchesCodec(t *testing.T) {
+	codec, err := goavro.NewCodec(cursorSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := codec.BinaryEncode(nil, map[string]interface{}{
+		"n": nil, "b": true, "i": int32(-5), "l": int64(1 << 40), "f": float32(1.5), "d": -2.25,
+		"by": []byte("by"), "s": "str", "fx": []byte("xy"),
+		"a": []interface{}{int32(1), int32(2)}, "m": map[string]interface{}{"k": int64(3)},
+		"u": goavro.Union("string", "u"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f goavro.Cursor
+	ints := []int32{1, 2}
+	steps := []func() error{
+		func() error { return f.EncodeNull() },
+		func() error { return f.EncodeBoolean(true) },
+		func() error { return f.EncodeInt(-5) },
+		func() error { return f.EncodeLong(1 << 40) },
+		func() error { return f.EncodeFloat(1.5) },
+		func() error { return f.EncodeDouble(-2.25) },
+		func() error { return f.EncodeBytes([]byte("by")) },
+		func() error { return f.EncodeString("str") },
+		func() error { return f.EncodeFixed([]byte("xy"), 2) },
+		func() error {
+			return f.EncodeBlocks(len(ints), func(i int) error { return f.EncodeInt(ints[i]) })
+		},
+		func() error {
+			return f.EncodeBlocks(1, func(int) error {
+				if err := f.EncodeString("k"); err != nil {
+					return err
+				}
+				return f.EncodeLong(3)
+			})
+		},
+		func() error {
+			if err := f.EncodeUnionIndex(1); err != nil {
+				return err
+			}
+			return f.EncodeString("u")
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if actual := []byte(f); !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestCursorDecode(t *testing.T) {
+	codec, err := goavro.NewCodec(cursorSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryEncode(nil, map[string]interface{}{
+		"n": nil, "b": true, "i": int32(-5), "l": int64(1 << 40), "f": float32(1.5), "d": -2.25,
+		"by": []byte("by"), "s": "str", "fx": []byte("xy"),
+		"a": []interface{}{int32(1), int32(2)}, "m": map[string]interface{}{"k": int64(3)},
+		"u": goavro.Union("string", "u"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := goavro.Cursor(buf)
+	check := func(actual, expected interface{}, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+		}
+	}
+	check(nil, nil, f.DecodeNull())
+	b, err := f.DecodeBoolean()
+	check(b, true, err)
+	i, err := f.DecodeInt()
+	check(i, int32(-5), err)
+	l, err := f.DecodeLong()
+	check(l, int64(1<<40), err)
+	fl, err := f.DecodeFloat()
+	check(fl, float32(1.5), err)
+	d, err := f.DecodeDouble()
+	check(d, -2.25, err)
+	by, err := f.DecodeBytes()
+	check(by, []byte("by"), err)
+	s, err := f.DecodeString()
+	check(s, "str", err)
+	fx, err := f.DecodeFixed(2)
+	check(fx, []byte("xy"), err)
+
+	var items []int32
+	err = f.DecodeBlocks(func() error {
+		item, err := f.DecodeInt()
+		items = append(items, item)
+		return err
+	})
+	check(items, []int32{1, 2}, err)
+
+	values := make(map[string]int64)
+	err = f.DecodeBlocks(func() error {
+		key, err := f.DecodeString()
+		if err != nil {
+			return err
+		}
+		values[key], err = f.DecodeLong()
+		return err
+	})
+	check(values, map[string]int64{"k": 3}, err)
+
+	index, err := f.DecodeUnionIndex(2)
+	check(index, 1, err)
+	s, err = f.DecodeString()
+	check(s, "u", err)
+
+	if len(f) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", f, nil)
+	}
+}
+
+func TestCursorSkip(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"map","values":{"type":"array","items":"string"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryEncode(nil, map[string]interface{}{"k": []interface{}{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := goavro.Cursor(append(buf, 0x02))
+	if err = f.Skip(codec); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := []byte(f), []byte{0x02}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestCursorFail(t *testing.T) {
+	buf := goavro.AppendLong(nil, 5)
+	f := goavro.Cursor(buf)
+	if _, err := f.DecodeUnionIndex(2); err == nil || !strings.Contains(err.Error(), "index ought to be between 0 and 1") {
+		t.Errorf("Actual: %v; Expected: %s", err, "index ought to be between 0 and 1")
+	}
+	if _, err := f.DecodeBoolean(); err == nil {
+		t.Errorf("Actual: %v; Expected: %s", err, "error")
+	}
+	if _, err := f.DecodeFixed(2); err == nil {
+		t.Errorf("Actual: %v; Expected: %s", err, "short buffer")
+	}
+	if _, err := f.DecodeFixed(-1); err == nil || !strings.Contains(err.Error(), "negative size") {
+		t.Errorf("Actual: %v; Expected: %s", err, "negative size")
+	}
+	// NOTE: Cursor ought to be left unchanged on error.
+	if actual, expected := []byte(f), buf; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	if err := f.EncodeFixed([]byte("abc"), 2); err == nil || !strings.Contains(err.Error(), "expected: 2 bytes; received: 3") {
+		t.Errorf("Actual: %v; Expected: %s", err, "expected: 2 bytes")
+	}
+}
+
+func TestCursorAllocations(t *testing.T) {
+	buf := goavro.AppendString(goavro.AppendLong(nil, 42), "name")
+	allocs := testing.AllocsPerRun(100, func() {
+		f := goavro.Cursor(buf)
+		if _, err := f.DecodeLong(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.DecodeBytes(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Actual: %v; Expected: %v", allocs, 0)
+	}
+}
@@ -0,0 +1,166 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// makeDecimalCodec returns a codec for the decimal logical type, which annotates either bytes or a
+// fixed type.  The underlying bytes store the two's-complement representation of the unscaled
+// integer value in big-endian byte order, and the value of the decimal is the unscaled integer
+// multiplied by ten raised to the negative scale.  When size is greater than zero, the underlying
+// type is a fixed of that size, and values are sign extended to fill it.
+//
+// Decoding returns a *big.Rat.  Encoding accepts a *big.Rat, a decimal string such as "12.34", or a
+// *big.Int, which is taken to be the unscaled value, and is therefore interpreted using the scale
+// from the schema.
+func makeDecimalCodec(c *Codec, schemaMap map[string]interface{}, size int) (*Codec, error) {
+	precision, err := decimalAttribute(schemaMap, "precision", -1)
+	if err != nil {
+		return nil, err
+	}
+	if precision <= 0 {
+		return nil, fmt.Errorf("decimal precision ought to be greater than zero: %d", precision)
+	}
+	scale, err := decimalAttribute(schemaMap, "scale", 0)
+	if err != nil {
+		return nil, err
+	}
+	if scale < 0 || scale > precision {
+		return nil, fmt.Errorf("decimal scale ought to be between 0 and precision %d: %d", precision, scale)
+	}
+	if size > 0 {
+		if maxPrecision := decimalMaxPrecision(size); precision > maxPrecision {
+			return nil, fmt.Errorf("decimal precision ought to be no greater than %d for fixed size %d: %d", maxPrecision, size, precision)
+		}
+	}
+
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+
+	toNative := func(raw interface{}) (interface{}, error) {
+		unscaled := bytesToTwosComplement(raw.([]byte)) // bytes and fixed decoders always return []byte
+		return new(big.Rat).SetFrac(unscaled, denominator), nil
+	}
+	fromNative := func(datum interface{}) (interface{}, error) {
+		unscaled, err := decimalUnscaledFromDatum(datum, denominator)
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).Abs(unscaled).Cmp(limit) >= 0 {
+			return nil, fmt.Errorf("decimal: value exceeds precision %d: %s", precision, unscaled)
+		}
+		value := twosComplementToBytes(unscaled)
+		if size > 0 {
+			if len(value) > size {
+				return nil, fmt.Errorf("decimal: value requires %d bytes, exceeding fixed size %d", len(value), size)
+			}
+			value = signExtend(value, size)
+		}
+		return value, nil
+	}
+
+	return makeLogicalCodec(c, toNative, fromNative), nil
+}
+
+// decimalAttribute returns the non-negative integer value of the named schema attribute, or the
+// default value when the attribute is not present.
+func decimalAttribute(schemaMap map[string]interface{}, key string, defaultValue int) (int, error) {
+	v, ok := schemaMap[key]
+	if !ok {
+		if defaultValue < 0 {
+			return 0, fmt.Errorf("decimal ought to have %s key", key)
+		}
+		return defaultValue, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f != float64(int(f)) {
+		return 0, fmt.Errorf("decimal %s ought to be an integer: %v", key, v)
+	}
+	return int(f), nil
+}
+
+// decimalMaxPrecision returns the maximum number of base 10 digits that may be stored in a two's
+// complement integer of the specified number of bytes, namely floor(log10(2^(8*size-1) - 1)).
+func decimalMaxPrecision(size int) int {
+	max := new(big.Int).Lsh(big.NewInt(1), uint(8*size-1))
+	max.Sub(max, big.NewInt(1))
+	return len(max.String()) - 1
+}
+
+// decimalUnscaledFromDatum returns the unscaled integer value of the datum, which is the datum
+// multiplied by the denominator derived from the scale.  It returns an error when the datum cannot
+// be represented at that scale without losing precision.
+func decimalUnscaledFromDatum(datum interface{}, denominator *big.Int) (*big.Int, error) {
+	var r *big.Rat
+	switch v := datum.(type) {
+	case *big.Rat:
+		if v == nil {
+			return nil, errors.New("decimal: expected: Go *big.Rat, *big.Int, or string; received: nil *big.Rat")
+		}
+		r = v
+	case *big.Int:
+		if v == nil {
+			return nil, errors.New("decimal: expected: Go *big.Rat, *big.Int, or string; received: nil *big.Int")
+		}
+		return v, nil // already unscaled
+	case string:
+		var ok bool
+		if r, ok = new(big.Rat).SetString(v); !ok {
+			return nil, fmt.Errorf("decimal: cannot parse string: %q", v)
+		}
+	default:
+		return nil, fmt.Errorf("decimal: expected: Go *big.Rat, *big.Int, or string; received: %T", datum)
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(denominator))
+	if !scaled.IsInt() {
+		return nil, fmt.Errorf("decimal: provided value would lose precision: %s", r.RatString())
+	}
+	return new(big.Int).Set(scaled.Num()), nil
+}
+
+// bytesToTwosComplement returns the integer value of the big-endian two's-complement bytes.
+func bytesToTwosComplement(buf []byte) *big.Int {
+	i := new(big.Int).SetBytes(buf)
+	if len(buf) > 0 && buf[0]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*len(buf))))
+	}
+	return i
+}
+
+// twosComplementToBytes returns the shortest big-endian two's-complement representation of i.
+func twosComplementToBytes(i *big.Int) []byte {
+	if i.Sign() >= 0 {
+		buf := i.Bytes()
+		if len(buf) == 0 || buf[0]&0x80 != 0 {
+			buf = append([]byte{0}, buf...) // high bit must be clear for non-negative values
+		}
+		return buf
+	}
+	// For negative values, the number of bytes required is derived from the magnitude of -i - 1,
+	// which must fit in the bits below the sign bit.
+	magnitude := new(big.Int).Neg(i)
+	magnitude.Sub(magnitude, big.NewInt(1))
+	size := magnitude.BitLen()/8 + 1
+	v := new(big.Int).Lsh(big.NewInt(1), uint(8*size))
+	v.Add(v, i)
+	return signExtend(v.Bytes(), size)
+}
+
+// signExtend returns buf padded on the left to the specified size using its sign bit.
+func signExtend(buf []byte, size int) []byte {
+	if len(buf) >= size {
+		return buf
+	}
+	var pad byte
+	if len(buf) > 0 && buf[0]&0x80 != 0 {
+		pad = 0xff
+	}
+	extended := make([]byte, size)
+	for i := 0; i < size-len(buf); i++ {
+		extended[i] = pad
+	}
+	copy(extended[size-len(buf):], buf)
+	return extended
+}
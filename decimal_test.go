@@ -0,0 +1,73 @@
+package goavro_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestDecimalBytes(t *testing.T) {
+	schema := `{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}`
+	testBinaryCodecPass(t, schema, big.NewRat(1234, 100), []byte("\x04\x04\xd2"))
+	testBinaryCodecPass(t, schema, big.NewRat(-1234, 100), []byte("\x04\xfb\x2e"))
+	testBinaryCodecPass(t, schema, big.NewRat(0, 1), []byte("\x02\x00"))
+	testBinaryCodecPass(t, schema, big.NewRat(127, 100), []byte("\x02\x7f"))
+	testBinaryCodecPass(t, schema, big.NewRat(128, 100), []byte("\x04\x00\x80"))
+	testBinaryCodecPass(t, schema, big.NewRat(-128, 100), []byte("\x02\x80"))
+	testBinaryCodecPass(t, schema, big.NewRat(-129, 100), []byte("\x04\xff\x7f"))
+	testBinaryEncodePass(t, schema, "12.34", []byte("\x04\x04\xd2"))
+	testBinaryEncodePass(t, schema, big.NewInt(1234), []byte("\x04\x04\xd2")) // unscaled value
+}
+
+func TestDecimalFixed(t *testing.T) {
+	schema := `{"type":"fixed","name":"money","size":4,"logicalType":"decimal","precision":9,"scale":2}`
+	testBinaryCodecPass(t, schema, big.NewRat(1234, 100), []byte("\x00\x00\x04\xd2"))
+	testBinaryCodecPass(t, schema, big.NewRat(-1234, 100), []byte("\xff\xff\xfb\x2e"))
+	testBinaryEncodeFail(t, schema, "12345678.90", "exceeds precision")
+}
+
+func TestDecimalFixedReferencedByName(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"fixed","name":"money","size":2,"logicalType":"decimal","precision":4}},{"name":"f2","type":"money"}]}`
+	testBinaryCodecPass(t, schema, map[string]interface{}{"f1": big.NewRat(1, 1), "f2": big.NewRat(-1, 1)}, []byte("\x00\x01\xff\xff"))
+}
+
+func TestDecimalEncodeFail(t *testing.T) {
+	schema := `{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}`
+	testBinaryEncodeFail(t, schema, "1.234", "would lose precision")
+	testBinaryEncodeFail(t, schema, "100", "exceeds precision")
+	testBinaryEncodeFail(t, schema, "twelve", "cannot parse string")
+	testBinaryEncodeFail(t, schema, 12.34, "expected: Go *big.Rat, *big.Int, or string")
+}
+
+func TestDecimalInvalidFallsBackToUnderlyingType(t *testing.T) {
+	// missing precision
+	testBinaryCodecPass(t, `{"type":"bytes","logicalType":"decimal"}`, []byte("\x04\xd2"), []byte("\x04\x04\xd2"))
+	// scale greater than precision
+	testBinaryCodecPass(t, `{"type":"bytes","logicalType":"decimal","precision":2,"scale":3}`, []byte("\x04\xd2"), []byte("\x04\x04\xd2"))
+	// precision too large for fixed size
+	testBinaryCodecPass(t, `{"type":"fixed","name":"f1","size":1,"logicalType":"decimal","precision":3}`, []byte("\x7f"), []byte("\x7f"))
+}
+
+func TestDecimalDoesNotModifyPrimitive(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"bytes","logicalType":"decimal","precision":4}},{"name":"f2","type":"bytes"}]}`
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, _, err := codec.BinaryDecode([]byte("\x02\x01\x02\x01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := datum.(map[string]interface{})
+	if _, ok := record["f1"].(*big.Rat); !ok {
+		t.Errorf("Actual: %T; Expected: %T", record["f1"], &big.Rat{})
+	}
+	if _, ok := record["f2"].([]byte); !ok {
+		t.Errorf("Actual: %T; Expected: %T", record["f2"], []byte{})
+	}
+}
+
+func TestDecimalText(t *testing.T) {
+	testTextCodecPass(t, `{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}`, big.NewRat(1234, 100), []byte(`"\u0004\u00d2"`))
+}
@@ -0,0 +1,52 @@
+package goavro
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// durationSize is the size of the fixed type annotated by the duration logical type.
+const durationSize = 12
+
+// Duration is the native Go representation of the Avro duration logical type, which annotates a
+// fixed of size 12.  Each component is stored as an unsigned little-endian 32-bit integer, and the
+// components are independent of one another, because the number of days in a month and the number
+// of milliseconds in a day vary.
+type Duration struct {
+	Months       uint32
+	Days         uint32
+	Milliseconds uint32
+}
+
+func (d Duration) String() string {
+	return fmt.Sprintf("%d months %d days %d milliseconds", d.Months, d.Days, d.Milliseconds)
+}
+
+func durationToNative(raw interface{}) (interface{}, error) {
+	buf := raw.([]byte) // fixed decoder always returns []byte of the correct size
+	return Duration{
+		Months:       binary.LittleEndian.Uint32(buf[0:4]),
+		Days:         binary.LittleEndian.Uint32(buf[4:8]),
+		Milliseconds: binary.LittleEndian.Uint32(buf[8:12]),
+	}, nil
+}
+
+func durationFromNative(datum interface{}) (interface{}, error) {
+	var d Duration
+	switch v := datum.(type) {
+	case Duration:
+		d = v
+	case *Duration:
+		if v == nil {
+			return nil, fmt.Errorf("duration: expected: Go Duration; received: %T", datum)
+		}
+		d = *v
+	default:
+		return datum, nil // let fixed encoder handle raw bytes
+	}
+	buf := make([]byte, durationSize)
+	binary.LittleEndian.PutUint32(buf[0:4], d.Months)
+	binary.LittleEndian.PutUint32(buf[4:8], d.Days)
+	binary.LittleEndian.PutUint32(buf[8:12], d.Milliseconds)
+	return buf, nil
+}
@@ -0,0 +1,19 @@
+package goavro_test
+
+import (
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestDuration(t *testing.T) {
+	schema := `{"type":"fixed","name":"d","size":12,"logicalType":"duration"}`
+	testBinaryCodecPass(t, schema, goavro.Duration{Months: 1, Days: 2, Milliseconds: 3}, []byte("\x01\x00\x00\x00\x02\x00\x00\x00\x03\x00\x00\x00"))
+	testBinaryEncodePass(t, schema, &goavro.Duration{Months: 0x01020304}, []byte("\x04\x03\x02\x01\x00\x00\x00\x00\x00\x00\x00\x00"))
+	testTextCodecPass(t, schema, goavro.Duration{Days: 1}, []byte(`"\u0000\u0000\u0000\u0000\u0001\u0000\u0000\u0000\u0000\u0000\u0000\u0000"`))
+}
+
+func TestDurationRequiresSize12(t *testing.T) {
+	// NOTE: The logical type is ignored, and the underlying fixed type is used.
+	testBinaryCodecPass(t, `{"type":"fixed","name":"d","size":4,"logicalType":"duration"}`, []byte("abcd"), []byte("abcd"))
+}
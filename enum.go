@@ -32,6 +32,28 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		}
 		symbols[i] = symbol
 	}
+	c.kind = "enum"
+	c.symbols = symbols
+
+	// NOTE: The enum default is the symbol used when a reader encounters a symbol written with a
+	// writer schema that is not among the symbols of the reader schema.
+	if d, ok := schemaMap["default"]; ok {
+		symbolDefault, ok := d.(string)
+		if !ok {
+			return nil, fmt.Errorf("Enum %q default ought to be string; received: %T", c.typeName, d)
+		}
+		var found bool
+		for _, symbol := range symbols {
+			if symbol == symbolDefault {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Enum %q default ought to be member of symbols: %v; %q", c.typeName, symbols, symbolDefault)
+		}
+		c.symbolDefault = symbolDefault
+	}
 
 	c.binaryDecoder = func(buf []byte) (interface{}, []byte, error) {
 		var value interface{}
@@ -47,6 +69,16 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		}
 		return symbols[index], buf, nil
 	}
+	c.binarySkipper = func(buf []byte) ([]byte, error) {
+		index, rest, err := ReadLong(buf)
+		if err != nil {
+			return buf, fmt.Errorf("cannot skip Enum %q: index: %s", c.typeName, err)
+		}
+		if index < 0 || index >= int64(len(symbols)) {
+			return buf, fmt.Errorf("cannot skip Enum %q: index ought to be between 0 and %d; read index: %d", c.typeName, len(symbols)-1, index)
+		}
+		return rest, nil
+	}
 	c.binaryEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
 		someString, ok := datum.(string)
 		if !ok {
@@ -59,6 +91,42 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		}
 		return buf, fmt.Errorf("cannot encode Enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
 	}
+	c.textDecoder = func(buf []byte) (interface{}, []byte, error) {
+		someString, buf, err := textDecodeString(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode textual Enum %q: %s", c.typeName, err)
+		}
+		for _, symbol := range symbols {
+			if symbol == someString {
+				return symbol, buf, nil
+			}
+		}
+		return nil, buf, fmt.Errorf("cannot decode textual Enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+	}
+	c.textEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
+		someString, ok := datum.(string)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode textual Enum %q: expected string; received: %T", c.typeName, datum)
+		}
+		for _, symbol := range symbols {
+			if symbol == someString {
+				return appendTextString(buf, someString), nil
+			}
+		}
+		return buf, fmt.Errorf("cannot encode textual Enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+	}
+	c.defaultDecoder = func(datum interface{}) (interface{}, error) {
+		someString, ok := datum.(string)
+		if !ok {
+			return nil, fmt.Errorf("Enum %q default value ought to be JSON string; received: %T", c.typeName, datum)
+		}
+		for _, symbol := range symbols {
+			if symbol == someString {
+				return symbol, nil
+			}
+		}
+		return nil, fmt.Errorf("Enum %q default value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+	}
 
 	return c, nil
 }
@@ -0,0 +1,238 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// View refers to one binary encoded datum within a byte slice, along with the Codec for its
+// schema.  A View is returned by Find, and allows reading a nested datum without decoding the data
+// that encloses it.  It refers to the bytes of the slice it was found in, so callers that keep it
+// after modifying that slice ought to copy its bytes.
+type View struct {
+	codec *Codec
+	buf   []byte // binary encoding of the datum, sized to its extent
+	key   []byte // key of a map value passed to a ForEach callback
+}
+
+// Codec returns the Codec for the schema of the datum.
+func (v View) Codec() *Codec { return v.codec }
+
+// Bytes returns the binary encoding of the datum.
+func (v View) Bytes() []byte { return v.buf }
+
+// Key returns the key of the map value, for a View of a map value passed to a ForEach callback, and
+// nil otherwise.  The returned slice refers to the bytes of the encoded map, so callers that keep
+// the key after the callback returns ought to copy it, such as by converting it to a string.
+func (v View) Key() []byte { return v.key }
+
+// Decode returns the datum, decoded the same way as BinaryDecode.
+func (v View) Decode() (interface{}, error) {
+	if v.codec == nil {
+		return nil, errors.New("cannot decode zero View")
+	}
+	datum, _, err := v.codec.binaryDecoder(v.buf)
+	return datum, err
+}
+
+// Find returns a View of the datum at the path, relative to the datum of this View.  See
+// Codec.Find for the path syntax.
+func (v View) Find(path string) (View, error) {
+	if v.codec == nil {
+		return View{}, fmt.Errorf("cannot find %q: zero View", path)
+	}
+	return find(v.codec, v.buf, path)
+}
+
+// Find returns a View of the datum at the path within the binary encoded datum at the start of buf,
+// without decoding the data enclosing it.  Data preceding the datum, such as earlier record fields
+// and array items, is skipped using its encoded lengths rather than decoded.
+//
+// The path is a sequence of segments separated by slash, '/', each of which selects a nested datum
+// of the datum selected by the previous segments, interpreted according to its schema:
+//
+//   - for a record, the segment is the name of a field.
+//   - for an array, the segment is the decimal index of an item, starting at 0.
+//   - for a map, the segment is a key.
+//   - for a union, the segment is the full name of a member type, such as "string" or
+//     "com.example.Engineer", and ought to name the member used by the encoded datum.
+//
+// As with JSON Pointer, "~1" in a segment stands for '/', and "~0" stands for '~', so a map key
+// containing slashes may be found.  The empty path selects the datum at the start of buf.
+//
+//	engineers, err := codec.Find(buf, "some/engineers")
+//	if err != nil {
+//	    return err
+//	}
+//	name, err := engineers.Find("0/name")
+//
+// It returns an error when the path selects a datum not in the encoded data, such as an array
+// index beyond the end of the array, or a union member other than the encoded one.  Find cannot be
+// used with a resolving Codec returned by NewResolvingCodec, because the path follows the reader
+// schema while the data is encoded with the writer schema.
+func (c Codec) Find(buf []byte, path string) (View, error) {
+	if c.reader != nil {
+		return View{}, fmt.Errorf("cannot find %q: ought not to use resolving Codec", path)
+	}
+	if path == "" {
+		// NOTE: Only a View of the datum at the start of buf refers to the Codec itself, so only
+		// copy it for the empty path, and other paths do not allocate.
+		root := c
+		return newView(&root, buf, path)
+	}
+	found, buf, err := locatePath(&c, buf, path)
+	if err != nil {
+		return View{}, err
+	}
+	return newView(found, buf, path)
+}
+
+// pathUnescaper replaces the escape sequences of path segments with the characters they stand for.
+var pathUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// find returns a View of the datum at the path within the binary encoded datum at the start of buf.
+func find(c *Codec, buf []byte, path string) (View, error) {
+	c, buf, err := locate(c, buf, path)
+	if err != nil {
+		return View{}, err
+	}
+	return newView(c, buf, path)
+}
+
+// newView returns a View of the datum of the codec at the start of buf.
+func newView(c *Codec, buf []byte, path string) (View, error) {
+	end, err := c.binarySkipper(buf)
+	if err != nil {
+		return View{}, fmt.Errorf("cannot find %q: %s", path, err)
+	}
+	return View{codec: c, buf: buf[:len(buf)-len(end)]}, nil
+}
+
+// locate returns the codec of the datum at the path within the binary encoded datum at the start of
+// buf, and the byte slice starting with its encoding.
+func locate(c *Codec, buf []byte, path string) (*Codec, []byte, error) {
+	if path == "" {
+		return c, buf, nil
+	}
+	return locatePath(c, buf, path)
+}
+
+// locatePath is locate for a non-empty path.  It returns the codec of a datum within the datum of
+// the root codec, but never the root codec itself.
+func locatePath(root *Codec, buf []byte, path string) (*Codec, []byte, error) {
+	var c *Codec
+	rest := path
+	for {
+		segment := rest
+		i := strings.IndexByte(rest, '/')
+		if i >= 0 {
+			segment, rest = rest[:i], rest[i+1:]
+		}
+		if strings.IndexByte(segment, '~') >= 0 {
+			segment = pathUnescaper.Replace(segment)
+		}
+		parent := root
+		if c != nil {
+			parent = c
+		}
+		var err error
+		if c, buf, err = findSegment(parent, buf, segment); err != nil {
+			return nil, nil, fmt.Errorf("cannot find %q: %s", path, err)
+		}
+		if i < 0 {
+			return c, buf, nil
+		}
+	}
+}
+
+// findSegment returns the codec and the encoded bytes of the datum selected by the segment within
+// the datum at the start of buf.
+func findSegment(c *Codec, buf []byte, segment string) (*Codec, []byte, error) {
+	var err error
+	switch c.kind {
+	case "record":
+		for _, field := range c.fields {
+			if field.name == segment {
+				return field.codec, buf, nil
+			}
+			if buf, err = field.codec.binarySkipper(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot skip Record %q field %q: %s", c.typeName, field.name, err)
+			}
+		}
+		return nil, nil, fmt.Errorf("Record %q has no field %q", c.typeName, segment)
+	case "array":
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 {
+			return nil, nil, fmt.Errorf("array index ought to be non-negative integer: %q", segment)
+		}
+		for {
+			count, size, rest, err := readBlockHeader(buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			if count == 0 {
+				return nil, nil, fmt.Errorf("array index out of range: %s", segment)
+			}
+			buf = rest
+			if size >= 0 && int64(index) >= count {
+				// NOTE: Item not in this block, so jump over it using its byte size.
+				if size > int64(len(buf)) {
+					return nil, nil, io.ErrShortBuffer
+				}
+				buf = buf[size:]
+				index -= int(count)
+				continue
+			}
+			for ; count > 0; count-- {
+				if index == 0 {
+					return c.items, buf, nil
+				}
+				if buf, err = c.items.binarySkipper(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot skip array item: %s", err)
+				}
+				index--
+			}
+		}
+	case "map":
+		for {
+			count, rest, err := ReadBlockCount(buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			if count == 0 {
+				return nil, nil, fmt.Errorf("map has no key %q", segment)
+			}
+			buf = rest
+			for ; count > 0; count-- {
+				var key []byte
+				if key, buf, err = ReadBytes(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot decode map key: %s", err)
+				}
+				if string(key) == segment {
+					return c.items, buf, nil
+				}
+				if buf, err = c.items.binarySkipper(buf); err != nil {
+					return nil, nil, fmt.Errorf("cannot skip map value for key %q: %s", key, err)
+				}
+			}
+		}
+	case "union":
+		index, rest, err := ReadLong(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode union index: %s", err)
+		}
+		if index < 0 || index >= int64(len(c.members)) {
+			return nil, nil, fmt.Errorf("union index ought to be between 0 and %d; read index: %d", len(c.members)-1, index)
+		}
+		member := c.members[index]
+		if member.typeName.fullName != segment {
+			return nil, nil, fmt.Errorf("union member %q not encoded; encoded member: %q", segment, member.typeName.fullName)
+		}
+		return member, rest, nil
+	default:
+		return nil, nil, fmt.Errorf("cannot find %q in Avro type %s", segment, c.typeName)
+	}
+}
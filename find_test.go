@@ -0,0 +1,257 @@
+package goavro_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karrick/goavro"
+)
+
+const findSchema = `{"type":"record","name":"top","fields":[
+	{"name":"id","type":"long"},
+	{"name":"some","type":{"type":"record","name":"some","fields":[
+		{"name":"flag","type":"boolean"},
+		{"name":"engineers","type":{"type":"array","items":{"type":"record","name":"engineer","fields":[
+			{"name":"name","type":"string"},
+			{"name":"level","type":["null","int"]}]}}},
+		{"name":"teams","type":{"type":"map","values":"string"}}]}},
+	{"name":"tail","type":"string"}]}`
+
+func findBuffer(t *testing.T) (*goavro.Codec, []byte) {
+	codec, err := goavro.NewCodec(findSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryEncode(nil, map[string]interface{}{
+		"id": int64(42),
+		"some": map[string]interface{}{
+			"flag": true,
+			"engineers": []interface{}{
+				map[string]interface{}{"name": "alice", "level": nil},
+				map[string]interface{}{"name": "bob", "level": goavro.Union("int", 3)},
+			},
+			"teams": map[string]interface{}{"a/b": "slash", "db": "storage"},
+		},
+		"tail": "end",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return codec, buf
+}
+
+func TestFind(t *testing.T) {
+	codec, buf := findBuffer(t)
+	cases := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"id", int64(42)},
+		{"some/flag", true},
+		{"some/engineers/0/name", "alice"},
+		{"some/engineers/1/name", "bob"},
+		{"some/engineers/1/level", map[string]interface{}{"int": int32(3)}},
+		{"some/engineers/1/level/int", int32(3)},
+		{"some/teams/db", "storage"},
+		{"some/teams/a~1b", "slash"},
+		{"tail", "end"},
+	}
+	for _, c := range cases {
+		view, err := codec.Find(buf, c.path)
+		if err != nil {
+			t.Errorf("path: %q; %s", c.path, err)
+			continue
+		}
+		value, err := view.Decode()
+		if err != nil {
+			t.Errorf("path: %q; %s", c.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, c.expected) {
+			t.Errorf("path: %q; Actual: %#v; Expected: %#v", c.path, value, c.expected)
+		}
+	}
+}
+
+func TestFindView(t *testing.T) {
+	codec, buf := findBuffer(t)
+	engineers, err := codec.Find(buf, "some/engineers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The view ought to be sized to the extent of the array.
+	items, rest, err := engineers.Codec().BinaryDecode(engineers.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := len(items.([]interface{})), 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", rest, nil)
+	}
+	name, err := engineers.Find("1/name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := name.Bytes(), []byte("\x06bob"); !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	whole, err := codec.Find(append(buf, 0xff), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := whole.Bytes(), buf; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestFindArrayBlockSize(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"long"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: First block has negative count followed by its byte size, which Find uses to jump
+	// over it.  Its item bytes are invalid, so decoding them would fail.
+	buf := goavro.AppendLong(nil, -2)
+	buf = goavro.AppendLong(buf, 2)
+	buf = append(buf, 0xff, 0xff)
+	buf = goavro.AppendLong(buf, 2)
+	buf = goavro.AppendLong(buf, 10)
+	buf = goavro.AppendLong(buf, 20)
+	buf = goavro.AppendLong(buf, 0)
+
+	view, err := codec.Find(buf, "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := view.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, int64(20); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestFindFail(t *testing.T) {
+	codec, buf := findBuffer(t)
+	cases := []struct {
+		path         string
+		errorMessage string
+	}{
+		{"missing", `Record "top" has no field "missing"`},
+		{"some/engineers/2", "array index out of range: 2"},
+		{"some/engineers/x", "array index ought to be non-negative integer"},
+		{"some/teams/web", `map has no key "web"`},
+		{"some/engineers/0/level/int", `union member "int" not encoded; encoded member: "null"`},
+		{"id/x", `cannot find "x" in Avro type long`},
+	}
+	for _, c := range cases {
+		_, err := codec.Find(buf, c.path)
+		if err == nil || !strings.Contains(err.Error(), c.errorMessage) {
+			t.Errorf("path: %q; Actual: %v; Expected: %s", c.path, err, c.errorMessage)
+		}
+	}
+
+	if _, err := codec.Find(buf[:5], "tail"); err == nil {
+		t.Errorf("Actual: %v; Expected: %s", err, "short buffer")
+	}
+}
+
+func TestFindAllocations(t *testing.T) {
+	codec, buf := findBuffer(t)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := codec.Find(buf, "some/teams/db"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Actual: %v; Expected: %v", allocs, 0)
+	}
+}
+
+func TestFindSkipsEveryType(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"n","type":"null"},
+		{"name":"b","type":"boolean"},
+		{"name":"i","type":"int"},
+		{"name":"l","type":"long"},
+		{"name":"f","type":"float"},
+		{"name":"d","type":"double"},
+		{"name":"by","type":"bytes"},
+		{"name":"s","type":"string"},
+		{"name":"e","type":{"type":"enum","name":"e","symbols":["x","y"]}},
+		{"name":"fx","type":{"type":"fixed","name":"fx","size":3}},
+		{"name":"a","type":{"type":"array","items":"int"}},
+		{"name":"m","type":{"type":"map","values":"string"}},
+		{"name":"u","type":["null","string"]},
+		{"name":"ts","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"target","type":"string"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.BinaryEncode(nil, map[string]interface{}{
+		"n": nil, "b": true, "i": int32(-1000), "l": int64(1 << 40), "f": float32(1.5), "d": 2.5,
+		"by": []byte("bytes"), "s": "string", "e": "y", "fx": []byte("abc"),
+		"a": []interface{}{int32(1), int32(2)}, "m": map[string]interface{}{"k": "v"},
+		"u": goavro.Union("string", "u"), "ts": time.Unix(1, 0), "target": "found",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	view, err := codec.Find(buf, "target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := view.Bytes(), []byte("\x0afound"); !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := codec.Find(buf, "target"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Actual: %v; Expected: %v", allocs, 0)
+	}
+}
+
+func TestFindSkipsMapBlockSize(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"m","type":{"type":"map","values":"long"}},
+		{"name":"target","type":"long"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: Map block has negative count followed by its byte size, which is used to jump over
+	// the block.  Its bytes are invalid, so skipping its entries one by one would fail.
+	buf := goavro.AppendLong(nil, -1)
+	buf = goavro.AppendLong(buf, 3)
+	buf = append(buf, 0xff, 0xff, 0xff)
+	buf = goavro.AppendLong(buf, 0)
+	buf = goavro.AppendLong(buf, 7)
+
+	view, err := codec.Find(buf, "target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := view.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, int64(7); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	// A block size beyond the end of the buffer ought to fail.
+	buf = goavro.AppendLong(nil, -1)
+	buf = goavro.AppendLong(buf, 30)
+	if _, err = codec.Find(buf, "target"); err == nil || !strings.Contains(err.Error(), "cannot skip block") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot skip block")
+	}
+}
@@ -0,0 +1,51 @@
+package goavro
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+)
+
+// rabinEmpty is the CRC-64-AVRO fingerprint of the empty byte slice, and the seed from which the
+// fingerprint of every other byte slice is computed.
+const rabinEmpty = 0xc15d213aa4d7a795
+
+// rabinTable stores the CRC-64-AVRO fingerprint contribution of each possible byte value.
+var rabinTable = func() [256]uint64 {
+	var table [256]uint64
+	for i := range table {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			fp = (fp >> 1) ^ (rabinEmpty & -(fp & 1))
+		}
+		table[i] = fp
+	}
+	return table
+}()
+
+// Rabin returns the 64-bit Rabin fingerprint of the provided byte slice, using the CRC-64-AVRO
+// polynomial described in the Avro specification.
+func Rabin(buf []byte) uint64 {
+	fp := uint64(rabinEmpty)
+	for _, b := range buf {
+		fp = (fp >> 8) ^ rabinTable[byte(fp)^b]
+	}
+	return fp
+}
+
+// Fingerprint returns the 64-bit Rabin fingerprint of the Parsing Canonical Form of the Codec's
+// schema.  The Avro specification recommends this fingerprint for schema caches and for identifying
+// the schema of single-object encoded data.
+func (c Codec) Fingerprint() uint64 {
+	return Rabin([]byte(c.CanonicalSchema()))
+}
+
+// FingerprintMD5 returns the MD5 fingerprint of the Parsing Canonical Form of the Codec's schema.
+func (c Codec) FingerprintMD5() [md5.Size]byte {
+	return md5.Sum([]byte(c.CanonicalSchema()))
+}
+
+// FingerprintSHA256 returns the SHA-256 fingerprint of the Parsing Canonical Form of the Codec's
+// schema.
+func (c Codec) FingerprintSHA256() [sha256.Size]byte {
+	return sha256.Sum256([]byte(c.CanonicalSchema()))
+}
@@ -0,0 +1,77 @@
+package goavro_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestRabinEmpty(t *testing.T) {
+	if actual, expected := goavro.Rabin(nil), uint64(0xc15d213aa4d7a795); actual != expected {
+		t.Errorf("Actual: %#x; Expected: %#x", actual, expected)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	// NOTE: Expected values from the schema test cases published with the Avro specification.
+	cases := []struct {
+		schema   string
+		expected int64
+	}{
+		{`"null"`, 7195948357588979594},
+		{`{"type":"null"}`, 7195948357588979594},
+		{`"boolean"`, -6970731678124411036},
+		{`"int"`, 8247732601305521295},
+		{`"long"`, -3434872931120570953},
+		{`"float"`, 5583340709985441680},
+		{`"double"`, -8181574048448539266},
+		{`"bytes"`, 5746618253357095269},
+		{`"string"`, -8142146995180207161},
+	}
+	for _, tc := range cases {
+		c, err := goavro.NewCodec(tc.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := c.Fingerprint(), uint64(tc.expected); actual != expected {
+			t.Errorf("%s: Actual: %d; Expected: %d", tc.schema, int64(actual), tc.expected)
+		}
+	}
+}
+
+func TestFingerprintIgnoresNonCanonicalAttributes(t *testing.T) {
+	c1, err := goavro.NewCodec(`{"type":"enum","name":"e1","symbols":["A","B"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := goavro.NewCodec(`{"symbols":["A","B"],"doc":"some enum","name":"e1","type":"enum","default":"A"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := c2.Fingerprint(), c1.Fingerprint(); actual != expected {
+		t.Errorf("Actual: %#x; Expected: %#x", actual, expected)
+	}
+}
+
+func TestFingerprintMD5(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"enum","name":"e1","symbols":["A","B"],"doc":"some enum"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := c.FingerprintMD5()
+	if actual, expected := hex.EncodeToString(fp[:]), "bbcf2efabd0565579cdbdb48576ca2e0"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestFingerprintSHA256(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"int"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := c.FingerprintSHA256()
+	if actual, expected := hex.EncodeToString(fp[:]), "3f2b87a9fe7cc9b13835598c3981cd45e3e355309e5090aa0933d7becb6fba45"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
@@ -22,6 +22,8 @@ func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 		return nil, fmt.Errorf("Fixed %q size ought to be number greater than zero: %v", c.typeName, s1)
 	}
 	size := int(s2)
+	c.kind = "fixed"
+	c.size = size
 
 	c.binaryDecoder = func(buf []byte) (interface{}, []byte, error) {
 		if len(buf) < size {
@@ -29,6 +31,12 @@ func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 		}
 		return buf[:size], buf[size:], nil
 	}
+	c.binarySkipper = func(buf []byte) ([]byte, error) {
+		if len(buf) < size {
+			return buf, fmt.Errorf("Fixed %q short buffer: size exceeds remaining buffer length: %d > %d", c.typeName, size, len(buf))
+		}
+		return buf[size:], nil
+	}
 	c.binaryEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
 		var value []byte
 		switch v := datum.(type) {
@@ -44,6 +52,45 @@ func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 		}
 		return append(buf, value...), nil
 	}
+	c.textDecoder = func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := textDecodeBytes(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode textual Fixed %q: %s", c.typeName, err)
+		}
+		if count := len(value); count != size {
+			return nil, buf, fmt.Errorf("cannot decode textual Fixed %q: datum length ought to equal size: %d != %d", c.typeName, count, size)
+		}
+		return value, buf, nil
+	}
+	c.textEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
+		var value []byte
+		switch v := datum.(type) {
+		case string:
+			value = []byte(v)
+		case []byte:
+			value = v
+		default:
+			return buf, fmt.Errorf("cannot encode textual Fixed %q: expected string or bytes; received: %T", c.typeName, v)
+		}
+		if count := len(value); count != size {
+			return buf, fmt.Errorf("cannot encode textual Fixed %q: datum length ought to equal size: %d != %d", c.typeName, count, size)
+		}
+		return appendTextBytes(buf, value), nil
+	}
+	c.defaultDecoder = func(datum interface{}) (interface{}, error) {
+		someString, ok := datum.(string)
+		if !ok {
+			return nil, fmt.Errorf("Fixed %q default value ought to be JSON string; received: %T", c.typeName, datum)
+		}
+		value, err := bytesFromCodePoints(someString)
+		if err != nil {
+			return nil, fmt.Errorf("Fixed %q default value %s", c.typeName, err)
+		}
+		if count := len(value); count != size {
+			return nil, fmt.Errorf("Fixed %q default value length ought to equal size: %d != %d", c.typeName, count, size)
+		}
+		return value, nil
+	}
 
 	return c, nil
 }
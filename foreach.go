@@ -0,0 +1,84 @@
+package goavro
+
+import "fmt"
+
+// ForEach calls fn with a View of each item of the array, or each value of the map, at the path
+// within the binary encoded datum at the start of buf, in the order they are encoded.  The path
+// follows the syntax described for Find, and the empty path selects the datum at the start of buf.
+//
+// Neither the array or map nor its items are decoded: each View refers to the bytes of buf, sized
+// to the extent of the item, so the callback may decode only what it needs, such as with Get.  The
+// View of a map value also provides its key with the Key method.
+//
+//	err := codec.ForEach(buf, "some/engineers", func(engineer goavro.View) error {
+//	    name, err := engineer.Get("name")
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Printf("name: %q\n", name)
+//	    return nil
+//	})
+//
+// When fn returns an error, ForEach stops, and returns that error.  As with Find, ForEach cannot be
+// used with a resolving Codec returned by NewResolvingCodec.
+func (c Codec) ForEach(buf []byte, path string, fn func(View) error) error {
+	if c.reader != nil {
+		return fmt.Errorf("cannot iterate %q: ought not to use resolving Codec", path)
+	}
+	return forEach(&c, buf, path, fn)
+}
+
+// ForEach calls fn with a View of each item of the array, or each value of the map, at the path,
+// relative to the datum of this View.  See Codec.ForEach.
+func (v View) ForEach(path string, fn func(View) error) error {
+	if v.codec == nil {
+		return fmt.Errorf("cannot iterate %q: zero View", path)
+	}
+	return forEach(v.codec, v.buf, path, fn)
+}
+
+// Get returns the datum at the path, relative to the datum of this View, decoded the same way as
+// BinaryDecode.  It is equivalent to calling Find followed by Decode.
+func (v View) Get(path string) (interface{}, error) {
+	found, err := v.Find(path)
+	if err != nil {
+		return nil, err
+	}
+	return found.Decode()
+}
+
+// forEach calls fn with a View of each item of the array or map at the path.
+func forEach(c *Codec, buf []byte, path string, fn func(View) error) error {
+	c, buf, err := locate(c, buf, path)
+	if err != nil {
+		return err
+	}
+	if c.kind != "array" && c.kind != "map" {
+		return fmt.Errorf("cannot iterate %q: Avro type %s ought to be array or map", path, c.typeName)
+	}
+	for {
+		var count int64
+		if count, buf, err = ReadBlockCount(buf); err != nil {
+			return fmt.Errorf("cannot iterate %q: %s", path, err)
+		}
+		if count == 0 {
+			return nil
+		}
+		for ; count > 0; count-- {
+			var item View
+			if c.kind == "map" {
+				if item.key, buf, err = ReadBytes(buf); err != nil {
+					return fmt.Errorf("cannot iterate %q: cannot decode map key: %s", path, err)
+				}
+			}
+			start := buf
+			if buf, err = c.items.binarySkipper(buf); err != nil {
+				return fmt.Errorf("cannot iterate %q: %s", path, err)
+			}
+			item.codec, item.buf = c.items, start[:len(start)-len(buf)]
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+	}
+}
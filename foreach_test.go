@@ -0,0 +1,132 @@
+package goavro_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestForEachArray(t *testing.T) {
+	codec, buf := findBuffer(t)
+	var names []interface{}
+	err := codec.ForEach(buf, "some/engineers", func(engineer goavro.View) error {
+		if key := engineer.Key(); key != nil {
+			t.Errorf("Actual: %v; Expected: %v", key, nil)
+		}
+		name, err := engineer.Get("name")
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := names, []interface{}{"alice", "bob"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestForEachMap(t *testing.T) {
+	codec, buf := findBuffer(t)
+	some, err := codec.Find(buf, "some")
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams := make(map[string]interface{})
+	err = some.ForEach("teams", func(team goavro.View) error {
+		value, err := team.Decode()
+		if err != nil {
+			return err
+		}
+		teams[string(team.Key())] = value
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := teams, map[string]interface{}{"a/b": "slash", "db": "storage"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestForEachBlockSize(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"long"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := goavro.AppendLong(nil, -2)
+	buf = goavro.AppendLong(buf, 2)
+	buf = goavro.AppendLong(buf, 1)
+	buf = goavro.AppendLong(buf, 2)
+	buf = goavro.AppendLong(buf, 1)
+	buf = goavro.AppendLong(buf, 3)
+	buf = goavro.AppendLong(buf, 0)
+
+	var values []interface{}
+	err = codec.ForEach(buf, "", func(item goavro.View) error {
+		value, err := item.Decode()
+		values = append(values, value)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := values, []interface{}{int64(1), int64(2), int64(3)}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestForEachStop(t *testing.T) {
+	codec, buf := findBuffer(t)
+	stop := errors.New("stop")
+	var count int
+	err := codec.ForEach(buf, "some/engineers", func(goavro.View) error {
+		count++
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Actual: %v; Expected: %v", err, stop)
+	}
+	if actual, expected := count, 1; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestForEachFail(t *testing.T) {
+	codec, buf := findBuffer(t)
+	fn := func(goavro.View) error { return nil }
+	if err := codec.ForEach(buf, "id", fn); err == nil || !strings.Contains(err.Error(), "ought to be array or map") {
+		t.Errorf("Actual: %v; Expected: %s", err, "ought to be array or map")
+	}
+	if err := codec.ForEach(buf, "missing", fn); err == nil || !strings.Contains(err.Error(), `has no field "missing"`) {
+		t.Errorf("Actual: %v; Expected: %s", err, "has no field")
+	}
+	if err := (goavro.View{}).ForEach("", fn); err == nil {
+		t.Errorf("Actual: %v; Expected: %s", err, "zero View")
+	}
+}
+
+func TestForEachAllocations(t *testing.T) {
+	codec, buf := findBuffer(t)
+	var count int
+	fn := func(item goavro.View) error {
+		if _, err := item.Find("name"); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := codec.ForEach(buf, "some/engineers", fn); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Actual: %v; Expected: %v", allocs, 0)
+	}
+}
@@ -105,3 +105,78 @@ func testBinaryCodecPass(t *testing.T, schema string, datum interface{}, buf []b
 	testBinaryEncodePass(t, schema, datum, buf)
 	testBinaryDecodePass(t, schema, datum, buf)
 }
+
+func testTextDecodeFail(t *testing.T, schema string, buf []byte, errorMessage string) {
+	c, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, newBuffer, err := c.TextDecode(buf)
+	if err == nil || !strings.Contains(err.Error(), errorMessage) {
+		t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+	}
+	if value != nil {
+		t.Errorf("Actual: %v; Expected: %v", value, nil)
+	}
+	if !bytes.Equal(buf, newBuffer) {
+		t.Errorf("Actual: %v; Expected: %v", newBuffer, buf)
+	}
+}
+
+func testTextEncodeFail(t *testing.T, schema string, datum interface{}, errorMessage string) {
+	c, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := c.TextEncode(nil, datum)
+	if err == nil || !strings.Contains(err.Error(), errorMessage) {
+		t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+	}
+	if buf != nil {
+		t.Errorf("Actual: %v; Expected: %v", buf, nil)
+	}
+}
+
+func testTextDecodePass(t *testing.T, schema string, datum interface{}, encoded []byte) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, remaining, err := codec.TextDecode(encoded)
+	if err != nil {
+		t.Fatalf("schema: %s; %s", schema, err)
+	}
+
+	// remaining ought to be empty because there is nothing remaining to be decoded
+	if actual, expected := len(remaining), 0; actual != expected {
+		t.Errorf("schema: %s; Datum: %v; Actual: %#v; Expected: %#v", schema, datum, actual, expected)
+	}
+
+	// for testing purposes, to prevent big switch statement, convert each to string and compare.
+	if actual, expected := fmt.Sprintf("%v", value), fmt.Sprintf("%v", datum); actual != expected {
+		t.Errorf("schema: %s; Datum: %v; Actual: %#v; Expected: %#v", schema, datum, actual, expected)
+	}
+}
+
+func testTextEncodePass(t *testing.T, schema string, datum interface{}, expected []byte) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatalf("Schema: %q %s", schema, err)
+	}
+
+	actual, err := codec.TextEncode(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; Datum: %v; %s", schema, datum, err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("schema: %s; Datum: %v; Actual: %q; Expected: %q", schema, datum, actual, expected)
+	}
+}
+
+// testTextCodecPass does a bi-directional codec check, by encoding datum to text, then decoding
+// text back to datum.
+func testTextCodecPass(t *testing.T, schema string, datum interface{}, buf []byte) {
+	testTextEncodePass(t, schema, datum, buf)
+	testTextDecodePass(t, schema, datum, buf)
+}
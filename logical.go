@@ -0,0 +1,280 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// LogicalType describes a custom logical type, which annotates one or more underlying Avro types,
+// and provides the hooks used to convert between values of the underlying type and their native Go
+// representation.
+type LogicalType struct {
+	// Name is the value of the logicalType attribute in the schema, (required).
+	Name string
+
+	// UnderlyingTypes lists the Avro types the logical type may annotate, for instance "fixed",
+	// "record", or "string", (required).  When a schema specifies the logical type on any other
+	// type, the logical type is ignored and the underlying type is used, as required by the Avro
+	// specification.
+	UnderlyingTypes []string
+
+	// ToNative converts a value decoded by the underlying type to its native Go representation,
+	// (required).
+	ToNative func(interface{}) (interface{}, error)
+
+	// FromNative converts a native Go value to a value the underlying type can encode,
+	// (required).
+	FromNative func(interface{}) (interface{}, error)
+}
+
+var (
+	logicalTypesLock sync.RWMutex
+	logicalTypes     = make(map[string]LogicalType)
+)
+
+// builtinLogicalTypes are the logical types defined by the Avro specification, which may not be
+// replaced by custom logical types.
+var builtinLogicalTypes = map[string]struct{}{
+	"date":                   {},
+	"decimal":                {},
+	"duration":               {},
+	"local-timestamp-micros": {},
+	"local-timestamp-millis": {},
+	"time-micros":            {},
+	"time-millis":            {},
+	"timestamp-micros":       {},
+	"timestamp-millis":       {},
+	"uuid":                   {},
+}
+
+// avroTypeNames are the names of the Avro types a logical type may annotate.
+var avroTypeNames = map[string]struct{}{
+	"array":   {},
+	"boolean": {},
+	"bytes":   {},
+	"double":  {},
+	"enum":    {},
+	"fixed":   {},
+	"float":   {},
+	"int":     {},
+	"long":    {},
+	"map":     {},
+	"null":    {},
+	"record":  {},
+	"string":  {},
+}
+
+// RegisterLogicalType registers a custom logical type.  Codecs subsequently created by NewCodec
+// wrap the codec for the underlying type whenever a schema specifies the logical type on one of
+// its allowed underlying types.  It returns an error when the logical type is invalid, when its
+// name is one of the logical types defined by the Avro specification, or when a logical type by
+// the same name has already been registered.
+func RegisterLogicalType(lt LogicalType) error {
+	if lt.Name == "" {
+		return errors.New("cannot register logical type without Name")
+	}
+	if _, ok := builtinLogicalTypes[lt.Name]; ok {
+		return fmt.Errorf("cannot register logical type defined by the Avro specification: %q", lt.Name)
+	}
+	if len(lt.UnderlyingTypes) == 0 {
+		return fmt.Errorf("cannot register logical type %q without UnderlyingTypes", lt.Name)
+	}
+	for _, typeName := range lt.UnderlyingTypes {
+		if _, ok := avroTypeNames[typeName]; !ok {
+			return fmt.Errorf("cannot register logical type %q with unknown underlying type: %q", lt.Name, typeName)
+		}
+	}
+	if lt.ToNative == nil || lt.FromNative == nil {
+		return fmt.Errorf("cannot register logical type %q without both ToNative and FromNative", lt.Name)
+	}
+
+	logicalTypesLock.Lock()
+	defer logicalTypesLock.Unlock()
+	if _, ok := logicalTypes[lt.Name]; ok {
+		return fmt.Errorf("cannot register logical type %q more than once", lt.Name)
+	}
+	lt.UnderlyingTypes = append([]string(nil), lt.UnderlyingTypes...) // prevent caller from mutating
+	logicalTypes[lt.Name] = lt
+	return nil
+}
+
+// customLogicalType returns the registered custom logical type with the specified name, provided it
+// may annotate the specified underlying type.
+func customLogicalType(logicalType, typeName string) (LogicalType, bool) {
+	logicalTypesLock.RLock()
+	lt, ok := logicalTypes[logicalType]
+	logicalTypesLock.RUnlock()
+	if !ok {
+		return lt, false
+	}
+	for _, underlying := range lt.UnderlyingTypes {
+		if underlying == typeName {
+			return lt, true
+		}
+	}
+	return lt, false
+}
+
+// buildCodecForLogicalType returns a codec for the logical type specified in the schema map,
+// wrapping the provided codec for the underlying Avro type.  The Avro specification requires
+// implementations to ignore unknown logical types, and logical types whose attributes are invalid,
+// in which case the codec for the underlying type is returned unchanged.
+func buildCodecForLogicalType(typeName string, c *Codec, schemaMap map[string]interface{}) (*Codec, error) {
+	logicalType, ok := schemaMap["logicalType"].(string)
+	if !ok {
+		return c, nil
+	}
+
+	var lc *Codec
+	var err error
+
+	switch logicalType {
+	case "decimal":
+		switch typeName {
+		case "bytes":
+			lc, err = makeDecimalCodec(c, schemaMap, 0)
+		case "fixed":
+			lc, err = makeDecimalCodec(c, schemaMap, int(schemaMap["size"].(float64))) // size already vetted by makeFixedCodec
+		}
+	case "duration":
+		if typeName == "fixed" && schemaMap["size"].(float64) == durationSize { // size already vetted by makeFixedCodec
+			lc = makeLogicalCodec(c, durationToNative, durationFromNative)
+		}
+	case "uuid":
+		if typeName == "string" {
+			lc = makeLogicalCodec(c, uuidToNative, uuidFromNative)
+		}
+	case "date":
+		if typeName == "int" {
+			lc = makeLogicalCodec(c, dateToNative, dateFromNative)
+		}
+	case "time-millis":
+		if typeName == "int" {
+			lc = makeLogicalCodec(c, timeMillisToNative, timeMillisFromNative)
+		}
+	case "time-micros":
+		if typeName == "long" {
+			lc = makeLogicalCodec(c, timeMicrosToNative, timeMicrosFromNative)
+		}
+	case "timestamp-millis":
+		if typeName == "long" {
+			lc = makeLogicalCodec(c, timestampMillisToNative, timestampMillisFromNative)
+		}
+	case "timestamp-micros":
+		if typeName == "long" {
+			lc = makeLogicalCodec(c, timestampMicrosToNative, timestampMicrosFromNative)
+		}
+	case "local-timestamp-millis":
+		if typeName == "long" {
+			lc = makeLogicalCodec(c, timestampMillisToNative, localTimestampMillisFromNative)
+		}
+	case "local-timestamp-micros":
+		if typeName == "long" {
+			lc = makeLogicalCodec(c, timestampMicrosToNative, localTimestampMicrosFromNative)
+		}
+	default:
+		if lt, ok := customLogicalType(logicalType, typeName); ok {
+			lc = makeLogicalCodec(c, lt.ToNative, lt.FromNative)
+		}
+	}
+
+	if lc == nil || err != nil {
+		return c, nil // NOTE: unknown or invalid logical type; use underlying type
+	}
+	lc.logicalType = logicalType
+	lc.logicalProperties = logicalProperties(schemaMap)
+
+	switch typeName {
+	case "enum", "fixed", "record":
+		// NOTE: Named types are registered in the symbol table, and subsequent references to the
+		// type name ought to use the logical type as well, so update the codec in place.
+		*c = *lc
+		return c, nil
+	default:
+		// NOTE: Primitive type codecs are shared by every reference in the symbol table, so the
+		// logical type codec is a modified copy.
+		return lc, nil
+	}
+}
+
+// reservedAttributes are the schema attributes defined by the Avro specification for types other
+// than logical types.
+var reservedAttributes = map[string]struct{}{
+	"aliases": {}, "default": {}, "doc": {}, "fields": {}, "items": {}, "logicalType": {}, "name": {},
+	"namespace": {}, "order": {}, "size": {}, "symbols": {}, "type": {}, "values": {},
+}
+
+// logicalProperties returns the attributes of the schema map that are not reserved, such as the
+// precision and scale of a decimal, or nil when there are none.
+func logicalProperties(schemaMap map[string]interface{}) map[string]interface{} {
+	var properties map[string]interface{}
+	for k, v := range schemaMap {
+		if _, ok := reservedAttributes[k]; ok {
+			continue
+		}
+		if properties == nil {
+			properties = make(map[string]interface{})
+		}
+		properties[k] = v
+	}
+	return properties
+}
+
+// makeLogicalCodec returns a copy of the provided codec whose decoders convert each value decoded
+// by the underlying type using toNative, and whose encoders convert each datum using fromNative
+// before encoding it as the underlying type.
+func makeLogicalCodec(c *Codec, toNative func(interface{}) (interface{}, error), fromNative func(interface{}) (interface{}, error)) *Codec {
+	// NOTE: Capture the underlying functions rather than the codec, because the returned codec may
+	// replace the underlying codec in the symbol table.
+	binaryDecoder, binaryEncoder := c.binaryDecoder, c.binaryEncoder
+	textDecoder, textEncoder := c.textDecoder, c.textEncoder
+	defaultDecoder := c.defaultDecoder
+
+	underlying := *c
+	lc := *c
+	lc.underlying = &underlying
+	lc.toNative = toNative
+	lc.binaryDecoder = func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := binaryDecoder(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+		if value, err = toNative(value); err != nil {
+			return nil, buf, err
+		}
+		return value, buf, nil
+	}
+	lc.binaryEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
+		value, err := fromNative(datum)
+		if err != nil {
+			return buf, err
+		}
+		return binaryEncoder(buf, value)
+	}
+	lc.textDecoder = func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := textDecoder(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+		if value, err = toNative(value); err != nil {
+			return nil, buf, err
+		}
+		return value, buf, nil
+	}
+	lc.textEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
+		value, err := fromNative(datum)
+		if err != nil {
+			return buf, err
+		}
+		return textEncoder(buf, value)
+	}
+	lc.defaultDecoder = func(datum interface{}) (interface{}, error) {
+		value, err := defaultDecoder(datum)
+		if err != nil {
+			return nil, err
+		}
+		return toNative(value)
+	}
+	return &lc
+}
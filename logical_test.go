@@ -0,0 +1,88 @@
+package goavro_test
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+type geoPoint struct {
+	Lat, Lon float64
+}
+
+func init() {
+	if err := goavro.RegisterLogicalType(goavro.LogicalType{
+		Name:            "ip-address",
+		UnderlyingTypes: []string{"fixed"},
+		ToNative: func(raw interface{}) (interface{}, error) {
+			return net.IP(raw.([]byte)), nil
+		},
+		FromNative: func(datum interface{}) (interface{}, error) {
+			ip, ok := datum.(net.IP)
+			if !ok {
+				return nil, fmt.Errorf("ip-address: expected: net.IP; received: %T", datum)
+			}
+			return []byte(ip.To16()), nil
+		},
+	}); err != nil {
+		panic(err)
+	}
+	if err := goavro.RegisterLogicalType(goavro.LogicalType{
+		Name:            "geo-point",
+		UnderlyingTypes: []string{"record"},
+		ToNative: func(raw interface{}) (interface{}, error) {
+			m := raw.(map[string]interface{})
+			return geoPoint{Lat: m["lat"].(float64), Lon: m["lon"].(float64)}, nil
+		},
+		FromNative: func(datum interface{}) (interface{}, error) {
+			p, ok := datum.(geoPoint)
+			if !ok {
+				return nil, fmt.Errorf("geo-point: expected: geoPoint; received: %T", datum)
+			}
+			return map[string]interface{}{"lat": p.Lat, "lon": p.Lon}, nil
+		},
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func TestCustomLogicalTypeFixed(t *testing.T) {
+	schema := `{"type":"fixed","name":"ip","size":16,"logicalType":"ip-address"}`
+	testBinaryCodecPass(t, schema, net.ParseIP("192.168.1.1"), []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\xff\xff\xc0\xa8\x01\x01"))
+	testBinaryEncodeFail(t, schema, "192.168.1.1", "ip-address: expected: net.IP")
+}
+
+func TestCustomLogicalTypeRecord(t *testing.T) {
+	schema := `{"type":"record","name":"location","logicalType":"geo-point","fields":[{"name":"lat","type":"double"},{"name":"lon","type":"double"}]}`
+	testBinaryCodecPass(t, schema, geoPoint{Lat: 1, Lon: -1}, []byte("\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\xbf"))
+	testTextCodecPass(t, schema, geoPoint{Lat: 1.5, Lon: 2}, []byte(`{"lat":1.5,"lon":2}`))
+}
+
+func TestCustomLogicalTypeWrongUnderlyingTypeIgnored(t *testing.T) {
+	testBinaryCodecPass(t, `{"type":"bytes","logicalType":"ip-address"}`, []byte("ab"), []byte("\x04ab"))
+}
+
+func TestRegisterLogicalTypeInvalid(t *testing.T) {
+	toNative := func(v interface{}) (interface{}, error) { return v, nil }
+	fromNative := func(v interface{}) (interface{}, error) { return v, nil }
+
+	for _, tc := range []struct {
+		lt      goavro.LogicalType
+		message string
+	}{
+		{goavro.LogicalType{UnderlyingTypes: []string{"int"}, ToNative: toNative, FromNative: fromNative}, "without Name"},
+		{goavro.LogicalType{Name: "decimal", UnderlyingTypes: []string{"int"}, ToNative: toNative, FromNative: fromNative}, "defined by the Avro specification"},
+		{goavro.LogicalType{Name: "custom", ToNative: toNative, FromNative: fromNative}, "without UnderlyingTypes"},
+		{goavro.LogicalType{Name: "custom", UnderlyingTypes: []string{"integer"}, ToNative: toNative, FromNative: fromNative}, "unknown underlying type"},
+		{goavro.LogicalType{Name: "custom", UnderlyingTypes: []string{"int"}, ToNative: toNative}, "without both ToNative and FromNative"},
+		{goavro.LogicalType{Name: "ip-address", UnderlyingTypes: []string{"fixed"}, ToNative: toNative, FromNative: fromNative}, "more than once"},
+	} {
+		err := goavro.RegisterLogicalType(tc.lt)
+		if err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("Actual: %v; Expected: %s", err, tc.message)
+		}
+	}
+}
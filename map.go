@@ -18,53 +18,22 @@ func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]i
 
 	return &Codec{
 		typeName: &name{"map", nullNamespace},
+		kind:     "map",
+		items:    valueCodec,
 		binaryDecoder: func(buf []byte) (interface{}, []byte, error) {
-			var err error
-			var value interface{}
-
-			if value, buf, err = longDecoder(buf); err != nil {
-				return nil, buf, fmt.Errorf("cannot decode Map block count: %s", err)
-			}
-			blockCount := value.(int64)
-
-			// NOTE: While below RAM optimization not necessary, many encoders will encode all
-			// key-value pairs in a single block.  We can optimize amount of RAM allocated by
-			// runtime for the map by initializing the map for that number of pairs.
-			initialSize := blockCount
-			if initialSize < 0 {
-				initialSize = -initialSize
-			}
-			mapValues := make(map[string]interface{}, initialSize)
-
-			for blockCount != 0 {
-				if blockCount < 0 {
-					// NOTE: Negative block count means following long is the block size, for which
-					// we have no use.
-					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longDecoder(buf); err != nil {
-						return nil, buf, fmt.Errorf("cannot decode Map block size: %s", err)
-					}
-				}
-				// Decode `blockCount` datum values from buffer
-				for i := int64(0); i < blockCount; i++ {
-					// first decode the key string
-					if value, buf, err = stringDecoder(buf); err != nil {
-						return nil, buf, fmt.Errorf("cannot decode Map key: %s", err)
-					}
-					key := value.(string) // string decoder always returns a string
-					// then decode the value
-					if value, buf, err = valueCodec.binaryDecoder(buf); err != nil {
-						return nil, buf, fmt.Errorf("cannot decode Map value for key %q: %s", key, err)
-					}
-					mapValues[key] = value
+			return genericMapBinaryDecoder(buf, valueCodec.binaryDecoder)
+		},
+		binarySkipper: func(buf []byte) ([]byte, error) {
+			return skipBlocks(buf, func(buf []byte) ([]byte, error) {
+				newBuf, err := stringSkipper(buf)
+				if err != nil {
+					return buf, fmt.Errorf("cannot skip Map key: %s", err)
 				}
-				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longDecoder(buf); err != nil {
-					return nil, buf, fmt.Errorf("cannot decode Map block count: %s", err)
+				if newBuf, err = valueCodec.binarySkipper(newBuf); err != nil {
+					return buf, fmt.Errorf("cannot skip Map value: %s", err)
 				}
-				blockCount = value.(int64)
-			}
-			return mapValues, buf, nil
+				return newBuf, nil
+			})
 		},
 		binaryEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
 			mapValues, ok := datum.(map[string]interface{})
@@ -86,5 +55,88 @@ func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]i
 			// always end with final blockCount of 0
 			return longEncoder(buf, 0)
 		},
+		textDecoder: func(buf []byte) (interface{}, []byte, error) {
+			mapValues, buf, err := genericMapTextDecoder(buf, valueCodec, nil)
+			if err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Map: %s", err)
+			}
+			return mapValues, buf, nil
+		},
+		textEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
+			mapValues, ok := datum.(map[string]interface{})
+			if !ok {
+				return buf, fmt.Errorf("cannot encode textual Map: expected: map[string]interface{}; received: %T", datum)
+			}
+			var err error
+			if buf, err = genericMapTextEncoder(buf, mapValues, valueCodec, nil); err != nil {
+				return buf, fmt.Errorf("cannot encode textual Map: %s", err)
+			}
+			return buf, nil
+		},
+		defaultDecoder: func(datum interface{}) (interface{}, error) {
+			pairs, ok := datum.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Map default value ought to be JSON object; received: %T", datum)
+			}
+			mapValues := make(map[string]interface{}, len(pairs))
+			for k, v := range pairs {
+				value, err := decodeDefault(valueCodec, v)
+				if err != nil {
+					return nil, fmt.Errorf("Map default value for key %q ought to match values schema: %s", k, err)
+				}
+				mapValues[k] = value
+			}
+			return mapValues, nil
+		},
 	}, nil
 }
+
+// genericMapBinaryDecoder decodes a map from buf, using valueDecoder to decode each value.
+func genericMapBinaryDecoder(buf []byte, valueDecoder func([]byte) (interface{}, []byte, error)) (interface{}, []byte, error) {
+	var err error
+	var value interface{}
+
+	if value, buf, err = longDecoder(buf); err != nil {
+		return nil, buf, fmt.Errorf("cannot decode Map block count: %s", err)
+	}
+	blockCount := value.(int64)
+
+	// NOTE: While below RAM optimization not necessary, many encoders will encode all
+	// key-value pairs in a single block.  We can optimize amount of RAM allocated by
+	// runtime for the map by initializing the map for that number of pairs.
+	initialSize := blockCount
+	if initialSize < 0 {
+		initialSize = -initialSize
+	}
+	mapValues := make(map[string]interface{}, initialSize)
+
+	for blockCount != 0 {
+		if blockCount < 0 {
+			// NOTE: Negative block count means following long is the block size, for which
+			// we have no use.
+			blockCount = -blockCount // convert to its positive equivalent
+			if _, buf, err = longDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode Map block size: %s", err)
+			}
+		}
+		// Decode `blockCount` datum values from buffer
+		for i := int64(0); i < blockCount; i++ {
+			// first decode the key string
+			if value, buf, err = stringDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode Map key: %s", err)
+			}
+			key := value.(string) // string decoder always returns a string
+			// then decode the value
+			if value, buf, err = valueDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode Map value for key %q: %s", key, err)
+			}
+			mapValues[key] = value
+		}
+		// Decode next blockCount from buffer, because there may be more blocks
+		if value, buf, err = longDecoder(buf); err != nil {
+			return nil, buf, fmt.Errorf("cannot decode Map block count: %s", err)
+		}
+		blockCount = value.(int64)
+	}
+	return mapValues, buf, nil
+}
@@ -119,6 +119,44 @@ func newNameFromSchemaMap(enclosingNamespace string, schemaMap map[string]interf
 	return newName(nameString, namespaceString, enclosingNamespace)
 }
 
+// aliasesFromSchemaMap returns the aliases listed in the schema, if any.
+func aliasesFromSchemaMap(schemaMap map[string]interface{}) ([]string, error) {
+	value, ok := schemaMap["aliases"]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema aliases, if provided, ought to be array of strings; received: %T", value)
+	}
+	aliases := make([]string, len(values))
+	for i, v := range values {
+		alias, ok := v.(string)
+		if !ok || alias == "" {
+			return nil, fmt.Errorf("schema alias %d ought to be non-empty string; received: %T", i+1, v)
+		}
+		aliases[i] = alias
+	}
+	return aliases, nil
+}
+
+// newAliasesFromSchemaMap returns the full names of the aliases of a named type.  Aliases that are
+// not fully qualified are relative to the namespace of the named type.
+func newAliasesFromSchemaMap(n *name, schemaMap map[string]interface{}) ([]string, error) {
+	aliases, err := aliasesFromSchemaMap(schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	for i, alias := range aliases {
+		an, err := newName(alias, nullNamespace, n.namespace)
+		if err != nil {
+			return nil, fmt.Errorf("schema alias %d: %s", i+1, err)
+		}
+		aliases[i] = an.fullName
+	}
+	return aliases, nil
+}
+
 // Equal returns true when two Name instances refer to the same Avro name; otherwise it returns
 // false.
 func (n name) Equal(n2 name) bool {
@@ -131,8 +169,13 @@ func (n name) String() string {
 
 // short returns the name without the prefixed namespace.
 func (n name) short() string {
-	if index := strings.LastIndexByte(n.fullName, '.'); index > -1 {
-		return n.fullName[index+1:]
+	return shortName(n.fullName)
+}
+
+// shortName returns the full name without the prefixed namespace.
+func shortName(fullName string) string {
+	if index := strings.LastIndexByte(fullName, '.'); index > -1 {
+		return fullName[index+1:]
 	}
-	return n.fullName
+	return fullName
 }
@@ -58,3 +58,23 @@ func TestNameWithoutDotsButWithEmptyNamespaceAndEnclosingName(t *testing.T) {
 		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
 	}
 }
+
+func TestNameAliasesRelativeToNamespace(t *testing.T) {
+	n, err := newName("X", "org.foo", nullNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := newAliasesFromSchemaMap(n, map[string]interface{}{"aliases": []interface{}{"Y", "org.bar.Z"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := len(aliases), 2; actual != expected {
+		t.Fatalf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := aliases[0], "org.foo.Y"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+	if actual, expected := aliases[1], "org.bar.Z"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
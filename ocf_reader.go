@@ -33,6 +33,30 @@ const readBlockSize = 4096 // read and process data by blocks
 // NewOCFReader initializes and returns a new structure used to read an Avro Object Container File
 // (OCF).
 func NewOCFReader(ior io.Reader) (*OCFReader, error) {
+	return newOCFReader(ior, "", nil)
+}
+
+// NewOCFReaderWithSchema initializes and returns a new structure used to read an Avro Object
+// Container File (OCF), resolving each datum from the schema stored in the file to the provided
+// reader schema.
+func NewOCFReaderWithSchema(ior io.Reader, readerSchema string) (*OCFReader, error) {
+	return newOCFReader(ior, readerSchema, nil)
+}
+
+// NewOCFReaderWithProjection initializes and returns a new structure used to read an Avro Object
+// Container File (OCF), decoding only the fields of each datum selected by the field paths, as
+// described for ProjectSchema, and skipping the bytes of the other fields.
+func NewOCFReaderWithProjection(ior io.Reader, fieldPaths ...string) (*OCFReader, error) {
+	if len(fieldPaths) == 0 {
+		return nil, errors.New("cannot create OCFReader without field paths")
+	}
+	return newOCFReader(ior, "", fieldPaths)
+}
+
+// newOCFReader reads the OCF header from the provided io.Reader.  When readerSchema is not empty,
+// data items are resolved from the file schema to the reader schema, and when fieldPaths is not
+// empty, data items are projected to the field paths.
+func newOCFReader(ior io.Reader, readerSchema string, fieldPaths []string) (*OCFReader, error) {
 	// NOTE: Wrap provided io.Reader in a buffered reader, which provides
 	// io.ByteReader interface, along with improving the performance of
 	// streaming file data.
@@ -80,6 +104,19 @@ func NewOCFReader(ior io.Reader) (*OCFReader, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec from invalid avro.schema: %s", err)
 	}
+	if readerSchema != "" {
+		reader, err := NewCodec(readerSchema)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create codec from invalid reader schema: %s", err)
+		}
+		if bd, err = newResolvingCodec(bd, reader); err != nil {
+			return nil, err
+		}
+	} else if len(fieldPaths) > 0 {
+		if bd, err = newProjectedCodec(bd, fieldPaths); err != nil {
+			return nil, err
+		}
+	}
 
 	// read and store sync marker
 	sm := make([]byte, 16)
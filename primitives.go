@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"strconv"
 )
 
 func booleanDecoder(buf []byte) (interface{}, []byte, error) {
@@ -56,14 +57,9 @@ func bytesDecoder(buf []byte) (interface{}, []byte, error) {
 
 // receives string and []byte transparently
 func bytesEncoder(buf []byte, datum interface{}) ([]byte, error) {
-	var value []byte
-	switch v := datum.(type) {
-	case []byte:
-		value = v
-	case string:
-		value = []byte(v)
-	default:
-		return buf, fmt.Errorf("bytes: expected: Go string or []byte; received: %T", v)
+	value, err := bytesFromDatum(datum)
+	if err != nil {
+		return buf, err
 	}
 	// longEncoder only fails when given non int, so elide error checking
 	buf, _ = longEncoder(buf, len(value))
@@ -71,6 +67,17 @@ func bytesEncoder(buf []byte, datum interface{}) ([]byte, error) {
 	return append(buf, value...), nil
 }
 
+func bytesFromDatum(datum interface{}) ([]byte, error) {
+	switch v := datum.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("bytes: expected: Go string or []byte; received: %T", v)
+	}
+}
+
 func appendFloat(buf []byte, bits uint64, byteCount int) ([]byte, error) {
 	for i := 0; i < byteCount; i++ {
 		buf = append(buf, byte(bits&255))
@@ -91,31 +98,37 @@ func doubleDecoder(buf []byte) (interface{}, []byte, error) {
 // receives any Go numeric type and casts to float64, possibly with data loss if the value the
 // client sent is not represented in a float64.
 func doubleEncoder(buf []byte, datum interface{}) ([]byte, error) {
-	var value float64
+	value, err := doubleFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return appendFloat(buf, uint64(math.Float64bits(value)), doubleEncodedLength)
+}
+
+func doubleFromDatum(datum interface{}) (float64, error) {
 	switch v := datum.(type) {
 	case float64:
-		value = v
+		return v, nil
 	case float32:
-		value = float64(v)
+		return float64(v), nil
 	case int:
 		if int(float64(v)) != v {
-			return buf, fmt.Errorf("double: provided Go int would lose precision: %d", v)
+			return 0, fmt.Errorf("double: provided Go int would lose precision: %d", v)
 		}
-		value = float64(v)
+		return float64(v), nil
 	case int64:
 		if int64(float64(v)) != v {
-			return buf, fmt.Errorf("double: provided Go int64 would lose precision: %d", v)
+			return 0, fmt.Errorf("double: provided Go int64 would lose precision: %d", v)
 		}
-		value = float64(v)
+		return float64(v), nil
 	case int32:
 		if int32(float64(v)) != v {
-			return buf, fmt.Errorf("double: provided Go int32 would lose precision: %d", v)
+			return 0, fmt.Errorf("double: provided Go int32 would lose precision: %d", v)
 		}
-		value = float64(v)
+		return float64(v), nil
 	default:
-		return buf, fmt.Errorf("double: expected: Go numeric; received: %T", datum)
+		return 0, fmt.Errorf("double: expected: Go numeric; received: %T", datum)
 	}
-	return appendFloat(buf, uint64(math.Float64bits(value)), doubleEncodedLength)
 }
 
 const floatEncodedLength = 4 // float requires 4 bytes
@@ -130,35 +143,41 @@ func floatDecoder(buf []byte) (interface{}, []byte, error) {
 // receives any Go numeric type and casts to float32.  if cast is lossy, it returns an encoding
 // error
 func floatEncoder(buf []byte, datum interface{}) ([]byte, error) {
-	var value float32
+	value, err := floatFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return appendFloat(buf, uint64(math.Float32bits(value)), floatEncodedLength)
+}
+
+func floatFromDatum(datum interface{}) (float32, error) {
 	switch v := datum.(type) {
 	case float32:
-		value = v
+		return v, nil
 	case float64:
 		// Assume runtime can cast special floats correctly
 		if !math.IsNaN(v) && !math.IsInf(v, 1) && !math.IsInf(v, -1) && float64(float32(v)) != v {
-			return buf, fmt.Errorf("float: provided Go double would lose precision: %f", v)
+			return 0, fmt.Errorf("float: provided Go double would lose precision: %f", v)
 		}
-		value = float32(v)
+		return float32(v), nil
 	case int:
 		if int(float32(v)) != v {
-			return buf, fmt.Errorf("float: provided Go int would lose precision: %d", v)
+			return 0, fmt.Errorf("float: provided Go int would lose precision: %d", v)
 		}
-		value = float32(v)
+		return float32(v), nil
 	case int64:
 		if int64(float32(v)) != v {
-			return buf, fmt.Errorf("float: provided Go int64 would lose precision: %d", v)
+			return 0, fmt.Errorf("float: provided Go int64 would lose precision: %d", v)
 		}
-		value = float32(v)
+		return float32(v), nil
 	case int32:
 		if int32(float32(v)) != v {
-			return buf, fmt.Errorf("float: provided Go int32 would lose precision: %d", v)
+			return 0, fmt.Errorf("float: provided Go int32 would lose precision: %d", v)
 		}
-		value = float32(v)
+		return float32(v), nil
 	default:
-		return buf, fmt.Errorf("float: expected: Go numeric; received: %T", datum)
+		return 0, fmt.Errorf("float: expected: Go numeric; received: %T", datum)
 	}
-	return appendFloat(buf, uint64(math.Float32bits(value)), floatEncodedLength)
 }
 
 const (
@@ -200,35 +219,41 @@ func intDecoder(buf []byte) (interface{}, []byte, error) {
 // receives any Go numeric type and casts to int32, possibly with data loss if the value the client
 // sent is not represented in a int32.
 func intEncoder(buf []byte, datum interface{}) ([]byte, error) {
-	var value int32
+	value, err := intFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	encoded := uint64((uint32(value) << 1) ^ uint32(value>>intDownShift))
+	return appendInt(buf, encoded)
+}
+
+func intFromDatum(datum interface{}) (int32, error) {
 	switch v := datum.(type) {
 	case int:
 		if int(int32(v)) != v {
-			return buf, fmt.Errorf("int: provided Go int would lose precision: %d", v)
+			return 0, fmt.Errorf("int: provided Go int would lose precision: %d", v)
 		}
-		value = int32(v)
+		return int32(v), nil
 	case int64:
 		if int64(int32(v)) != v {
-			return buf, fmt.Errorf("int: provided Go int64 would lose precision: %d", v)
+			return 0, fmt.Errorf("int: provided Go int64 would lose precision: %d", v)
 		}
-		value = int32(v)
+		return int32(v), nil
 	case int32:
-		value = v
+		return v, nil
 	case float64:
 		if float64(int32(v)) != v {
-			return buf, fmt.Errorf("int: provided Go float64 would lose precision: %f", v)
+			return 0, fmt.Errorf("int: provided Go float64 would lose precision: %f", v)
 		}
-		value = int32(v)
+		return int32(v), nil
 	case float32:
 		if float32(int32(v)) != v {
-			return buf, fmt.Errorf("int: provided Go float32 would lose precision: %f", v)
+			return 0, fmt.Errorf("int: provided Go float32 would lose precision: %f", v)
 		}
-		value = int32(v)
+		return int32(v), nil
 	default:
-		return buf, fmt.Errorf("long: expected: Go numeric; received: %T", datum)
+		return 0, fmt.Errorf("int: expected: Go numeric; received: %T", datum)
 	}
-	encoded := uint64((uint32(value) << 1) ^ uint32(value>>intDownShift))
-	return appendInt(buf, encoded)
 }
 
 func longDecoder(buf []byte) (interface{}, []byte, error) {
@@ -249,29 +274,35 @@ func longDecoder(buf []byte) (interface{}, []byte, error) {
 // receives any Go numeric type and casts to int64, possibly with data loss if the value the client
 // sent is not represented in a int64.
 func longEncoder(buf []byte, datum interface{}) ([]byte, error) {
-	var value int64
+	value, err := longFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	encoded := (uint64(value) << 1) ^ uint64(value>>longDownShift)
+	return appendInt(buf, encoded)
+}
+
+func longFromDatum(datum interface{}) (int64, error) {
 	switch v := datum.(type) {
 	case int:
-		value = int64(v)
+		return int64(v), nil
 	case int64:
-		value = v
+		return v, nil
 	case int32:
-		value = int64(v)
+		return int64(v), nil
 	case float64:
 		if float64(int64(v)) != v {
-			return buf, fmt.Errorf("long: provided Go float64 would lose precision: %f", v)
+			return 0, fmt.Errorf("long: provided Go float64 would lose precision: %f", v)
 		}
-		value = int64(v)
+		return int64(v), nil
 	case float32:
 		if float32(int64(v)) != v {
-			return buf, fmt.Errorf("long: provided Go float64 would lose precision: %f", v)
+			return 0, fmt.Errorf("long: provided Go float64 would lose precision: %f", v)
 		}
-		value = int64(v)
+		return int64(v), nil
 	default:
-		return buf, fmt.Errorf("long: expected: Go numeric; received: %T", datum)
+		return 0, fmt.Errorf("long: expected: Go numeric; received: %T", datum)
 	}
-	encoded := (uint64(value) << 1) ^ uint64(value>>longDownShift)
-	return appendInt(buf, encoded)
 }
 
 func nullDecoder(buf []byte) (interface{}, []byte, error) { return nil, buf, nil }
@@ -318,3 +349,321 @@ func stringEncoder(buf []byte, datum interface{}) ([]byte, error) {
 	// append datum bytes
 	return append(buf, value...), nil
 }
+
+// NOTE: Below are the text decoders and encoders for the primitive types, which read and write the
+// JSON encoding described in the Avro specification.
+
+func booleanTextDecoder(buf []byte) (interface{}, []byte, error) {
+	var err error
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case hasPrefixLiteral(buf, "true"):
+		return true, buf[4:], nil
+	case hasPrefixLiteral(buf, "false"):
+		return false, buf[5:], nil
+	default:
+		return nil, nil, fmt.Errorf("boolean: expected: true or false; received: %q", buf[0])
+	}
+}
+
+func booleanTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, ok := datum.(bool)
+	if !ok {
+		return buf, fmt.Errorf("boolean: expected: Go bool; received: %T", datum)
+	}
+	return strconv.AppendBool(buf, value), nil
+}
+
+func bytesTextDecoder(buf []byte) (interface{}, []byte, error) {
+	value, buf, err := textDecodeBytes(buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bytes: %s", err)
+	}
+	return value, buf, nil
+}
+
+func bytesTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, err := bytesFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return appendTextBytes(buf, value), nil
+}
+
+// textDecodeFloat parses a JSON number, or one of the quoted strings "NaN", "Infinity", and
+// "-Infinity", which Avro implementations use to represent the special floating point values.
+func textDecodeFloat(buf []byte, bitSize int) (float64, []byte, error) {
+	var err error
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return 0, nil, err
+	}
+	if buf[0] == '"' {
+		var s string
+		if s, buf, err = textDecodeString(buf); err != nil {
+			return 0, nil, err
+		}
+		switch s {
+		case "NaN":
+			return math.NaN(), buf, nil
+		case "Infinity":
+			return math.Inf(1), buf, nil
+		case "-Infinity":
+			return math.Inf(-1), buf, nil
+		default:
+			return 0, nil, fmt.Errorf("expected: number; received: %q", s)
+		}
+	}
+	var token string
+	if token, buf, err = textNumberToken(buf); err != nil {
+		return 0, nil, err
+	}
+	value, err := strconv.ParseFloat(token, bitSize)
+	if err != nil {
+		return 0, nil, err
+	}
+	return value, buf, nil
+}
+
+// appendTextFloat appends the JSON representation of a floating point value.  Because JSON has no
+// representation for NaN and infinities, those are written as quoted strings.
+func appendTextFloat(buf []byte, value float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(value):
+		return append(buf, `"NaN"`...)
+	case math.IsInf(value, 1):
+		return append(buf, `"Infinity"`...)
+	case math.IsInf(value, -1):
+		return append(buf, `"-Infinity"`...)
+	default:
+		return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+	}
+}
+
+func doubleTextDecoder(buf []byte) (interface{}, []byte, error) {
+	value, buf, err := textDecodeFloat(buf, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("double: %s", err)
+	}
+	return value, buf, nil
+}
+
+func doubleTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, err := doubleFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return appendTextFloat(buf, value, 64), nil
+}
+
+func floatTextDecoder(buf []byte) (interface{}, []byte, error) {
+	value, buf, err := textDecodeFloat(buf, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("float: %s", err)
+	}
+	return float32(value), buf, nil
+}
+
+func floatTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, err := floatFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return appendTextFloat(buf, float64(value), 32), nil
+}
+
+func intTextDecoder(buf []byte) (interface{}, []byte, error) {
+	token, buf, err := textNumberToken(buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("int: %s", err)
+	}
+	value, err := strconv.ParseInt(token, 10, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("int: %s", err)
+	}
+	return int32(value), buf, nil
+}
+
+func intTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, err := intFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return strconv.AppendInt(buf, int64(value), 10), nil
+}
+
+func longTextDecoder(buf []byte) (interface{}, []byte, error) {
+	token, buf, err := textNumberToken(buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("long: %s", err)
+	}
+	value, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("long: %s", err)
+	}
+	return value, buf, nil
+}
+
+func longTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	value, err := longFromDatum(datum)
+	if err != nil {
+		return buf, err
+	}
+	return strconv.AppendInt(buf, value, 10), nil
+}
+
+func nullTextDecoder(buf []byte) (interface{}, []byte, error) {
+	buf, err := consumeLiteral(buf, "null")
+	if err != nil {
+		return nil, nil, fmt.Errorf("null: %s", err)
+	}
+	return nil, buf, nil
+}
+
+func nullTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	if datum != nil {
+		return buf, fmt.Errorf("null: expected: Go nil; received: %T", datum)
+	}
+	return append(buf, "null"...), nil
+}
+
+func stringTextDecoder(buf []byte) (interface{}, []byte, error) {
+	value, buf, err := textDecodeString(buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("string: %s", err)
+	}
+	return value, buf, nil
+}
+
+func stringTextEncoder(buf []byte, datum interface{}) ([]byte, error) {
+	switch v := datum.(type) {
+	case string:
+		return appendTextString(buf, v), nil
+	case []byte:
+		return appendTextString(buf, string(v)), nil
+	default:
+		return buf, fmt.Errorf("string: expected: Go string or []byte; received: %T", v)
+	}
+}
+
+// NOTE: Below are the default decoders for the primitive types, which convert a default value, as
+// unmarshaled from the JSON schema, to the native Go value the decoders for the type return.
+
+func booleanDefaultDecoder(datum interface{}) (interface{}, error) {
+	if _, ok := datum.(bool); !ok {
+		return nil, fmt.Errorf("boolean: expected: JSON boolean; received: %T", datum)
+	}
+	return datum, nil
+}
+
+func bytesDefaultDecoder(datum interface{}) (interface{}, error) {
+	s, ok := datum.(string)
+	if !ok {
+		return nil, fmt.Errorf("bytes: expected: JSON string; received: %T", datum)
+	}
+	value, err := bytesFromCodePoints(s)
+	if err != nil {
+		return nil, fmt.Errorf("bytes: %s", err)
+	}
+	return value, nil
+}
+
+func doubleDefaultDecoder(datum interface{}) (interface{}, error) {
+	return doubleFromDatum(datum)
+}
+
+func floatDefaultDecoder(datum interface{}) (interface{}, error) {
+	// NOTE: JSON numbers are unmarshaled as float64, and most decimal fractions cannot be
+	// represented exactly by either type, so allow the loss of precision.
+	if v, ok := datum.(float64); ok {
+		return float32(v), nil
+	}
+	return floatFromDatum(datum)
+}
+
+func intDefaultDecoder(datum interface{}) (interface{}, error) {
+	return intFromDatum(datum)
+}
+
+func longDefaultDecoder(datum interface{}) (interface{}, error) {
+	return longFromDatum(datum)
+}
+
+func nullDefaultDecoder(datum interface{}) (interface{}, error) {
+	if datum != nil {
+		return nil, fmt.Errorf("null: expected: JSON null; received: %T", datum)
+	}
+	return nil, nil
+}
+
+func stringDefaultDecoder(datum interface{}) (interface{}, error) {
+	if _, ok := datum.(string); !ok {
+		return nil, fmt.Errorf("string: expected: JSON string; received: %T", datum)
+	}
+	return datum, nil
+}
+
+// NOTE: Below are the binary skippers for the primitive types, which return the byte slice
+// following one encoded value without decoding it, and without allocating.
+
+func nullSkipper(buf []byte) ([]byte, error) { return buf, nil }
+
+func booleanSkipper(buf []byte) ([]byte, error) {
+	if len(buf) < 1 {
+		return buf, io.ErrShortBuffer
+	}
+	return buf[1:], nil
+}
+
+func bytesSkipper(buf []byte) ([]byte, error) {
+	size, rest, err := ReadLong(buf)
+	if err != nil {
+		return buf, fmt.Errorf("bytes: %s", err)
+	}
+	if size < 0 {
+		return buf, fmt.Errorf("bytes: negative length: %d", size)
+	}
+	if size > int64(len(rest)) {
+		return buf, io.ErrShortBuffer
+	}
+	return rest[size:], nil
+}
+
+func doubleSkipper(buf []byte) ([]byte, error) {
+	if len(buf) < doubleEncodedLength {
+		return buf, io.ErrShortBuffer
+	}
+	return buf[doubleEncodedLength:], nil
+}
+
+func floatSkipper(buf []byte) ([]byte, error) {
+	if len(buf) < floatEncodedLength {
+		return buf, io.ErrShortBuffer
+	}
+	return buf[floatEncodedLength:], nil
+}
+
+func stringSkipper(buf []byte) ([]byte, error) {
+	size, rest, err := ReadLong(buf)
+	if err != nil {
+		return buf, fmt.Errorf("string: %s", err)
+	}
+	if size < 0 {
+		return buf, fmt.Errorf("string: negative length: %d", size)
+	}
+	if size > int64(len(rest)) {
+		return buf, io.ErrShortBuffer
+	}
+	return rest[size:], nil
+}
+
+// longSkipper skips the variable length zig-zag encoding shared by int and long values.
+func longSkipper(buf []byte) ([]byte, error) {
+	for offset, b := range buf {
+		if b&intFlag == 0 {
+			return buf[offset+1:], nil
+		}
+	}
+	return buf, io.ErrShortBuffer
+}
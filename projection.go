@@ -0,0 +1,208 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProjectSchema returns the specification of the schema projected to the specified field paths:
+// a copy of the schema in which each record keeps only the fields selected by the paths.  Each
+// field path is a sequence of record field names separated by slash, '/', such as
+// "owner/address/city", starting from the top level record.  Arrays, maps, and unions are
+// transparent to field paths: a name selects the field of the records that are the items of an
+// array, the values of a map, or the members of a union.  For example, "engineers/name" selects
+// the name field of each record in the engineers array.
+//
+// A path that ends at a field of a record type selects all fields of that record.  Because a named
+// type is defined once, a record used at several places in the schema keeps the fields selected at
+// any of them, and a record that has no selected fields, such as a member of a union other than the
+// one having the selected field, keeps all of its fields.  It returns an error when a path does
+// not select a field of the schema.
+func ProjectSchema(schemaSpecification string, fieldPaths ...string) (string, error) {
+	c, err := NewCodec(schemaSpecification)
+	if err != nil {
+		return "", err
+	}
+	s, err := projectSchema(c.Schema(), fieldPaths)
+	if err != nil {
+		return "", err
+	}
+	return SchemaJSON(s)
+}
+
+// NewProjectedCodec returns a Codec whose BinaryDecode decodes data encoded with the specified
+// schema, returning records that contain only the fields selected by the field paths, as described
+// for ProjectSchema.  The bytes of the other fields are skipped without decoding them.
+//
+//	codec, err := goavro.NewProjectedCodec(schema, "id", "owner/name", "engineers/name")
+//
+// As with a Codec returned by NewResolvingCodec, only BinaryDecode and Unmarshal read data encoded
+// with the specified schema, while the other methods operate using the projected schema.
+func NewProjectedCodec(schemaSpecification string, fieldPaths ...string) (*Codec, error) {
+	writer, err := NewCodec(schemaSpecification)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create codec for schema: %s", err)
+	}
+	return newProjectedCodec(writer, fieldPaths)
+}
+
+// newProjectedCodec returns a resolving codec whose reader schema is the projection of the writer
+// codec's schema to the field paths.
+func newProjectedCodec(writer *Codec, fieldPaths []string) (*Codec, error) {
+	s, err := projectSchema(writer.Schema(), fieldPaths)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := SchemaJSON(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create projected schema: %s", err)
+	}
+	reader, err := NewCodec(spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create codec for projected schema: %s", err)
+	}
+	return newResolvingCodec(writer, reader)
+}
+
+// projectSchema removes the fields not selected by the field paths from the records of the schema
+// tree, and returns the tree.
+func projectSchema(s Schema, fieldPaths []string) (Schema, error) {
+	if len(fieldPaths) == 0 {
+		return nil, errors.New("cannot project schema without field paths")
+	}
+	p := &projection{
+		selected: make(map[*RecordSchema]map[string]struct{}),
+		whole:    make(map[*RecordSchema]struct{}),
+	}
+	for _, fieldPath := range fieldPaths {
+		if err := p.selectPath(s, strings.Split(fieldPath, "/")); err != nil {
+			return nil, fmt.Errorf("cannot project field path %q: %s", fieldPath, err)
+		}
+	}
+	p.prune(s, make(map[*RecordSchema]struct{}))
+	return s, nil
+}
+
+// projection stores the record fields selected by field paths.
+type projection struct {
+	selected map[*RecordSchema]map[string]struct{} // names of selected fields of each record
+	whole    map[*RecordSchema]struct{}            // records whose fields are all selected
+}
+
+// selectPath selects the record field named by the first segment within the schema, and the
+// fields named by the remaining segments within the type of that field.
+func (p *projection) selectPath(s Schema, segments []string) error {
+	switch st := s.(type) {
+	case *ArraySchema:
+		return p.selectPath(st.Items, segments)
+	case *MapSchema:
+		return p.selectPath(st.Values, segments)
+	case *LogicalSchema:
+		return p.selectPath(st.Underlying, segments)
+	case *UnionSchema:
+		// NOTE: The path selects the field of each member that has it, and ought to select the
+		// field of at least one member other than null.
+		var firstErr error
+		var found bool
+		for _, member := range st.Members {
+			if member.Type() == "null" {
+				continue
+			}
+			if err := p.selectPath(member, segments); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			found = true
+		}
+		if !found {
+			if firstErr == nil {
+				return fmt.Errorf("cannot select field %q of union without members other than null", segments[0])
+			}
+			return firstErr
+		}
+		return nil
+	case *RecordSchema:
+		for _, field := range st.Fields {
+			if field.Name != segments[0] {
+				continue
+			}
+			if p.selected[st] == nil {
+				p.selected[st] = make(map[string]struct{})
+			}
+			p.selected[st][field.Name] = struct{}{}
+			if len(segments) == 1 {
+				p.selectAll(field.Type)
+				return nil
+			}
+			return p.selectPath(field.Type, segments[1:])
+		}
+		return fmt.Errorf("Record %q has no field %q", st.Name, segments[0])
+	default:
+		return fmt.Errorf("cannot select field %q of Avro type %s", segments[0], s.Type())
+	}
+}
+
+// selectAll selects all fields of the records within the schema.
+func (p *projection) selectAll(s Schema) {
+	switch st := s.(type) {
+	case *ArraySchema:
+		p.selectAll(st.Items)
+	case *MapSchema:
+		p.selectAll(st.Values)
+	case *LogicalSchema:
+		p.selectAll(st.Underlying)
+	case *UnionSchema:
+		for _, member := range st.Members {
+			p.selectAll(member)
+		}
+	case *RecordSchema:
+		if _, ok := p.whole[st]; ok {
+			return // NOTE: already visited, possibly through a recursive reference
+		}
+		p.whole[st] = struct{}{}
+		for _, field := range st.Fields {
+			p.selectAll(field.Type)
+		}
+	}
+}
+
+// prune removes the fields not selected from the records within the schema.  The visited map
+// stores the records already pruned.
+func (p *projection) prune(s Schema, visited map[*RecordSchema]struct{}) {
+	switch st := s.(type) {
+	case *ArraySchema:
+		p.prune(st.Items, visited)
+	case *MapSchema:
+		p.prune(st.Values, visited)
+	case *LogicalSchema:
+		p.prune(st.Underlying, visited)
+	case *UnionSchema:
+		for _, member := range st.Members {
+			p.prune(member, visited)
+		}
+	case *RecordSchema:
+		if _, ok := visited[st]; ok {
+			return
+		}
+		visited[st] = struct{}{}
+		selected, ok := p.selected[st]
+		if _, whole := p.whole[st]; ok && !whole {
+			fields := st.Fields[:0]
+			for _, field := range st.Fields {
+				if _, ok := selected[field.Name]; ok {
+					fields = append(fields, field)
+				}
+			}
+			st.Fields = fields
+		}
+		for _, field := range st.Fields {
+			// NOTE: Remove field aliases, so a field of the projected schema only matches the
+			// field of the same name in the original schema when resolving.
+			field.Aliases = nil
+			p.prune(field.Type, visited)
+		}
+	}
+}
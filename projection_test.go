@@ -0,0 +1,160 @@
+package goavro_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const projectionSchema = `{"type":"record","name":"team","fields":[
+	{"name":"id","type":"long"},
+	{"name":"blob","type":"bytes"},
+	{"name":"lead","type":["null",{"type":"record","name":"person","fields":[
+		{"name":"name","type":"string"},
+		{"name":"age","type":"int"},
+		{"name":"manager","type":["null","person"]}]}]},
+	{"name":"engineers","type":{"type":"array","items":"person"}},
+	{"name":"labels","type":{"type":"map","values":{"type":"record","name":"label","fields":[
+		{"name":"text","type":"string"},
+		{"name":"color","type":"string","aliases":["colour"]}]}}}]}`
+
+func projectionDatum() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   int64(7),
+		"blob": []byte("ignored"),
+		"lead": goavro.Union("person", map[string]interface{}{
+			"name": "alice", "age": int32(40),
+			"manager": goavro.Union("person", map[string]interface{}{"name": "carol", "age": int32(50), "manager": nil}),
+		}),
+		"engineers": []interface{}{
+			map[string]interface{}{"name": "bob", "age": int32(30), "manager": nil},
+		},
+		"labels": map[string]interface{}{
+			"x": map[string]interface{}{"text": "ex", "color": "red"},
+		},
+	}
+}
+
+func TestProjectSchema(t *testing.T) {
+	actual, err := goavro.ProjectSchema(projectionSchema, "id", "engineers/name", "labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"record","name":"team","fields":[` +
+		`{"name":"id","type":"long"},` +
+		`{"name":"engineers","type":{"type":"array","items":{"type":"record","name":"person","fields":[{"name":"name","type":"string"}]}}},` +
+		`{"name":"labels","type":{"type":"map","values":{"type":"record","name":"label","fields":[{"name":"text","type":"string"},{"name":"color","type":"string"}]}}}]}`
+	if actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestProjectSchemaSharedRecord(t *testing.T) {
+	// NOTE: The person record is used by both lead and engineers, so it keeps the fields selected
+	// through either of them.
+	actual, err := goavro.ProjectSchema(projectionSchema, "lead/name", "engineers/age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"record","name":"team","fields":[` +
+		`{"name":"lead","type":["null",{"type":"record","name":"person","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"}]}]},` +
+		`{"name":"engineers","type":{"type":"array","items":"person"}}]}`
+	if actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestProjectSchemaInvalid(t *testing.T) {
+	cases := []struct {
+		paths        []string
+		errorMessage string
+	}{
+		{nil, "without field paths"},
+		{[]string{"missing"}, `cannot project field path "missing": Record "team" has no field "missing"`},
+		{[]string{"id/x"}, `cannot select field "x" of Avro type long`},
+		{[]string{"lead/missing"}, `Record "person" has no field "missing"`},
+	}
+	for _, c := range cases {
+		_, err := goavro.ProjectSchema(projectionSchema, c.paths...)
+		if err == nil || !strings.Contains(err.Error(), c.errorMessage) {
+			t.Errorf("paths: %v; Actual: %v; Expected: %s", c.paths, err, c.errorMessage)
+		}
+	}
+}
+
+func TestProjectedCodec(t *testing.T) {
+	writer, err := goavro.NewCodec(projectionSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryEncode(nil, projectionDatum())
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec, err := goavro.NewProjectedCodec(projectionSchema, "id", "lead/manager/name", "labels/color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, rest, err := codec.BinaryDecode(append(buf, 0xff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	expected := "map[id:7 labels:map[x:map[color:red]] lead:map[person:map[manager:map[person:map[manager:<nil> name:carol]] name:alice]]]"
+	if actual := fmt.Sprintf("%v", value); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	type label struct {
+		Color string `avro:"color"`
+	}
+	type team struct {
+		ID     int64            `avro:"id"`
+		Labels map[string]label `avro:"labels"`
+	}
+	var decoded team
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID != 7 || decoded.Labels["x"].Color != "red" {
+		t.Errorf("Actual: %v; Expected: %v", decoded, team{ID: 7, Labels: map[string]label{"x": {Color: "red"}}})
+	}
+}
+
+func TestOCFReaderWithProjection(t *testing.T) {
+	bb := new(bytes.Buffer)
+	ocfw, err := goavro.NewOCFWriter(goavro.OCFWriterConfig{W: bb, Schema: projectionSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ocfw.Append([]interface{}{projectionDatum(), projectionDatum()}); err != nil {
+		t.Fatal(err)
+	}
+
+	ocfr, err := goavro.NewOCFReaderWithProjection(bb, "engineers/name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for ocfr.Scan() {
+		value, err := ocfr.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := fmt.Sprintf("%v", value), "map[engineers:[map[name:bob]]]"; actual != expected {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+		count++
+	}
+	if err = ocfr.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := count, 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
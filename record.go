@@ -4,6 +4,18 @@ import (
 	"fmt"
 )
 
+// recordField describes one field of a record.
+type recordField struct {
+	name         string
+	aliases      []string // alternate names used when resolving a writer field
+	doc          string
+	order        string // sort order of the field: "ascending", "descending", or "ignore"
+	codec        *Codec
+	hasDefault   bool
+	defaultValue interface{} // default value as unmarshaled from the JSON schema
+	nativeValue  interface{} // default value converted to its native Go value
+}
+
 func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	// NOTE: To support recursive data types, create the codec and register it using the specified
 	// name, and fill in the codec functions later.
@@ -21,9 +33,8 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		return nil, fmt.Errorf("Record %q fields ought to be non-empty array: %v", c.typeName, fields)
 	}
 
-	fieldCodecs := make([]*Codec, len(fieldSchemas))
-	fieldNames := make([]string, len(fieldSchemas))
-	fieldNameDuplicateCheck := make(map[string]struct{})
+	recordFields := make([]*recordField, len(fieldSchemas))
+	codecFromFieldName := make(map[string]*Codec, len(fieldSchemas))
 	for i, fieldSchema := range fieldSchemas {
 		fieldSchemaMap, ok := fieldSchema.(map[string]interface{})
 		if !ok {
@@ -45,28 +56,63 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 			return nil, fmt.Errorf("Record %q field %d ought to have valid name: %v", c.typeName, i+1, fieldSchemaMap)
 		}
 		fieldName := n.short()
-		if _, ok := fieldNameDuplicateCheck[fieldName]; ok {
+		if _, ok := codecFromFieldName[fieldName]; ok {
 			return nil, fmt.Errorf("Record %q field %d ought to have unique name: %q", c.typeName, i+1, fieldName)
 		}
-		fieldNameDuplicateCheck[fieldName] = struct{}{}
-		fieldNames[i] = fieldName
+		codecFromFieldName[fieldName] = fieldCodec
+
+		aliases, err := aliasesFromSchemaMap(fieldSchemaMap)
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %q ought to have valid aliases: %s", c.typeName, fieldName, err)
+		}
+		for _, alias := range aliases {
+			if err = checkNameComponent(alias); err != nil {
+				return nil, fmt.Errorf("Record %q field %q ought to have valid aliases: %s", c.typeName, fieldName, err)
+			}
+		}
 
-		fieldCodecs[i] = fieldCodec
+		field := &recordField{name: fieldName, aliases: aliases, codec: fieldCodec, order: "ascending"}
+		field.doc, _ = fieldSchemaMap["doc"].(string)
+		if order, ok := fieldSchemaMap["order"]; ok {
+			switch order {
+			case "ascending", "descending", "ignore":
+				field.order = order.(string)
+			default:
+				return nil, fmt.Errorf("Record %q field %q order ought to be ascending, descending, or ignore; received: %v", c.typeName, fieldName, order)
+			}
+		}
+		// NOTE: Default values are converted to native Go values after the codec functions are
+		// filled in below, so a field whose type refers back to this record may have a default
+		// value.
+		field.defaultValue, field.hasDefault = fieldSchemaMap["default"]
+		recordFields[i] = field
 	}
+	c.kind = "record"
+	c.fields = recordFields
 
 	c.binaryDecoder = func(buf []byte) (interface{}, []byte, error) {
-		recordMap := make(map[string]interface{}, len(fieldCodecs))
-		for i, fieldCodec := range fieldCodecs {
+		recordMap := make(map[string]interface{}, len(recordFields))
+		for _, field := range recordFields {
 			var value interface{}
 			var err error
-			value, buf, err = fieldCodec.binaryDecoder(buf)
+			value, buf, err = field.codec.binaryDecoder(buf)
 			if err != nil {
 				return nil, buf, err
 			}
-			recordMap[fieldNames[i]] = value
+			recordMap[field.name] = value
 		}
 		return recordMap, buf, nil
 	}
+	c.binarySkipper = func(buf []byte) ([]byte, error) {
+		rest := buf
+		var err error
+		for _, field := range recordFields {
+			if rest, err = field.codec.binarySkipper(rest); err != nil {
+				return buf, fmt.Errorf("cannot skip Record %q field %q: %s", c.typeName, field.name, err)
+			}
+		}
+		return rest, nil
+	}
 	c.binaryEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
 		valueMap, ok := datum.(map[string]interface{})
 		if !ok {
@@ -74,14 +120,18 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		}
 
 		// records encoded in order fields were defined in schema
-		for i, fieldCodec := range fieldCodecs {
-			fieldName := fieldNames[i]
+		for _, field := range recordFields {
+			fieldName := field.name
 
-			// NOTE: If field value was not specified in map, then attempt to encode the nil
+			// NOTE: If field value was not specified in map, then encode its default value if it
+			// has one, or attempt to encode the nil
 			fieldValue, ok := valueMap[fieldName]
+			if !ok && field.hasDefault {
+				fieldValue, ok = field.nativeValue, true
+			}
 
 			var err error
-			buf, err = fieldCodec.binaryEncoder(buf, fieldValue)
+			buf, err = field.codec.binaryEncoder(buf, fieldValue)
 			if err != nil {
 				if !ok {
 					return buf, fmt.Errorf("Record %q field value for %q was not specified", c.typeName, fieldName)
@@ -92,6 +142,92 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		}
 		return buf, nil
 	}
+	c.textDecoder = func(buf []byte) (interface{}, []byte, error) {
+		recordMap, buf, err := genericMapTextDecoder(buf, nil, codecFromFieldName)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode textual Record %q: %s", c.typeName, err)
+		}
+		for _, field := range recordFields {
+			if _, ok := recordMap[field.name]; !ok {
+				return nil, buf, fmt.Errorf("cannot decode textual Record %q: field value for %q was not specified", c.typeName, field.name)
+			}
+		}
+		return recordMap, buf, nil
+	}
+	c.textEncoder = func(buf []byte, datum interface{}) ([]byte, error) {
+		valueMap, ok := datum.(map[string]interface{})
+		if !ok {
+			return buf, fmt.Errorf("Record %q value ought to be map[string]interface{}; received: %T", c.typeName, datum)
+		}
+
+		// records encoded in order fields were defined in schema
+		buf = append(buf, '{')
+		for i, field := range recordFields {
+			fieldName := field.name
+
+			// NOTE: If field value was not specified in map, then encode its default value if it
+			// has one, or attempt to encode the nil
+			fieldValue, ok := valueMap[fieldName]
+			if !ok && field.hasDefault {
+				fieldValue, ok = field.nativeValue, true
+			}
+
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendTextString(buf, fieldName)
+			buf = append(buf, ':')
+
+			var err error
+			buf, err = field.codec.textEncoder(buf, fieldValue)
+			if err != nil {
+				if !ok {
+					return buf, fmt.Errorf("Record %q field value for %q was not specified", c.typeName, fieldName)
+				}
+				// field was specified in datum; therefore its value was invalid
+				return buf, fmt.Errorf("Record %q field value for %q does not match its schema: %s", c.typeName, fieldName, err)
+			}
+		}
+		return append(buf, '}'), nil
+	}
+	c.defaultDecoder = func(datum interface{}) (interface{}, error) {
+		valueMap, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Record %q default value ought to be JSON object; received: %T", c.typeName, datum)
+		}
+		recordMap := make(map[string]interface{}, len(recordFields))
+		for _, field := range recordFields {
+			fieldValue, ok := valueMap[field.name]
+			if !ok {
+				if field.hasDefault {
+					recordMap[field.name] = field.nativeValue
+					continue
+				}
+				return nil, fmt.Errorf("Record %q default value ought to specify field %q", c.typeName, field.name)
+			}
+			value, err := decodeDefault(field.codec, fieldValue)
+			if err != nil {
+				return nil, fmt.Errorf("Record %q default value for field %q ought to match its schema: %s", c.typeName, field.name, err)
+			}
+			recordMap[field.name] = value
+		}
+		return recordMap, nil
+	}
+
+	// Convert and type check each default value, ensuring the field codec can encode it.
+	for _, field := range recordFields {
+		if !field.hasDefault {
+			continue
+		}
+		nativeValue, err := decodeDefault(field.codec, field.defaultValue)
+		if err == nil {
+			_, err = field.codec.binaryEncoder(nil, nativeValue)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %q default value ought to match field schema: %s", c.typeName, field.name, err)
+		}
+		field.nativeValue = nativeValue
+	}
 
 	return c, nil
 }
@@ -368,3 +368,52 @@ func TestRecordEncodeFail(t *testing.T) {
 	testBinaryEncodeFail(t, schema, map[string]interface{}{"f1": "foo"}, `field value for "f2" was not specified`)
 	testBinaryEncodeFail(t, schema, map[string]interface{}{"f1": "foo", "f2": 13}, `field value for "f2" does not match its schema`)
 }
+
+func TestRecordFieldDefaultValue(t *testing.T) {
+	testSchemaValid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":13}]}`)
+	testSchemaValid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"string","default":"foo"}]}`)
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":"foo"}]}`,
+		`Record "r1" field "f1" default value ought to match field schema`)
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":3.5}]}`,
+		`Record "r1" field "f1" default value ought to match field schema`)
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"]},"default":"charlie"}]}`,
+		`ought to be member of symbols`)
+}
+
+func TestRecordFieldDefaultValueEncoded(t *testing.T) {
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":13},{"name":"f2","type":"string","default":"ab"}]}`, map[string]interface{}{}, []byte("\x1a\x04ab"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":13}]}`, map[string]interface{}{"f1": 3}, []byte("\x06"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"bytes","default":"ÿ"}]}`, map[string]interface{}{}, []byte("\x02\xff"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"float","default":0.1}]}`, map[string]interface{}{}, []byte("\xcd\xcc\xcc\x3d"))
+	testTextEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","default":13}]}`, map[string]interface{}{}, []byte(`{"f1":13}`))
+}
+
+func TestRecordFieldUnionDefaultValue(t *testing.T) {
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","int"],"default":null}]}`, map[string]interface{}{}, []byte("\x00"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","null"],"default":13}]}`, map[string]interface{}{}, []byte("\x00\x1a"))
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","null"],"default":null}]}`,
+		`Union default value ought to match first member type "int"`)
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","int"],"default":13}]}`,
+		`Union default value ought to match first member type "null"`)
+}
+
+func TestRecordFieldComplexDefaultValue(t *testing.T) {
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":"int"},"default":[1,2]}]}`, map[string]interface{}{}, []byte("\x04\x02\x04\x00"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"map","values":"int"},"default":{"a":1}}]}`, map[string]interface{}{}, []byte("\x02\x02a\x02\x00"))
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"record","name":"r2","fields":[{"name":"f2","type":["null","int"]},{"name":"f3","type":"string","default":"x"}]},"default":{"f2":null}}]}`, map[string]interface{}{}, []byte("\x00\x02x"))
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":"int"},"default":["a"]}]}`,
+		`Array default value item 1 ought to match items schema`)
+	testSchemaInvalid(t,
+		`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"record","name":"r2","fields":[{"name":"f2","type":"int"}]},"default":{}}]}`,
+		`Record "r2" default value ought to specify field "f2"`)
+}
+
+func TestRecordFieldRecursiveDefaultValue(t *testing.T) {
+	testBinaryEncodePass(t, `{"type":"record","name":"list","fields":[{"name":"value","type":"int"},{"name":"next","type":["null","list"],"default":null}]}`, map[string]interface{}{"value": 1}, []byte("\x02\x00"))
+}
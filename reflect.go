@@ -0,0 +1,966 @@
+package goavro
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Marshal appends the binary encoding of the Go value v to buf, in accordance with the Codec's Avro
+// schema, and returns the new byte slice.  Unlike BinaryEncode, which requires the generic
+// representation of the datum, such as map[string]interface{} for records, Marshal maps Go values
+// to the schema using reflection:
+//
+//   - records are encoded from structs, whose fields are matched to record fields by the name in
+//     their `avro:"name"` tag, or by the Go field name when they have no tag; fields tagged
+//     `avro:"-"` are ignored, and fields of embedded structs are treated as fields of the
+//     enclosing struct.  Record fields without a matching struct field are encoded using their
+//     default value.
+//   - arrays are encoded from slices, maps from maps with string keys, enums from strings, fixed
+//     from byte arrays or byte slices, and bytes and string from either strings or byte slices.
+//   - unions of null and one other type are encoded from pointers, where a nil pointer is encoded
+//     as null.
+//   - logical types are encoded from their native Go values, such as time.Time for the
+//     timestamp-millis logical type and *big.Rat for the decimal logical type, or from pointers to
+//     them, or from the values pointed to by them.
+//   - values of interface types, such as interface{}, are encoded from the generic representation
+//     expected by BinaryEncode.
+//   - records, enums, and fixed are encoded by values of types whose pointers implement
+//     Marshaler, Unmarshaler, and NamedType, reporting the named type of the schema, which encode
+//     themselves.
+//
+// The plan used to map each Go type to the schema is built once, and cached with the Codec.  On
+// error, it returns the original byte slice without any encoded bytes.
+func (c Codec) Marshal(buf []byte, v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return c.BinaryEncode(buf, nil)
+	}
+	plan, err := c.reflectPlan(rv.Type())
+	if err != nil {
+		return buf, fmt.Errorf("cannot marshal Go type %s: %s", rv.Type(), err)
+	}
+	newBuf, err := plan.encode(buf, rv)
+	if err != nil {
+		return buf, fmt.Errorf("cannot marshal Go type %s: %s", rv.Type(), err)
+	}
+	return newBuf, nil
+}
+
+// Unmarshal decodes the binary encoded datum at the start of buf into the Go value pointed to by v,
+// which ought to be a non-nil pointer, using the same mapping between Go values and the Codec's
+// Avro schema as Marshal.  Struct fields without a matching record field are left unchanged, and
+// record fields without a matching struct field are skipped.  On success, it returns the byte
+// slice with the decoded bytes consumed.  On error, it returns the original byte slice without any
+// bytes consumed and the error.
+func (c Codec) Unmarshal(buf []byte, v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return buf, fmt.Errorf("cannot unmarshal into Go value: ought to be non-nil pointer; received: %T", v)
+	}
+	plan, err := c.reflectPlan(rv.Type().Elem())
+	if err != nil {
+		return buf, fmt.Errorf("cannot unmarshal into Go type %s: %s", rv.Type().Elem(), err)
+	}
+	newBuf, err := plan.decode(buf, rv.Elem())
+	if err != nil {
+		return buf, fmt.Errorf("cannot unmarshal into Go type %s: %s", rv.Type().Elem(), err)
+	}
+	return newBuf, nil
+}
+
+// Marshaler is the interface implemented by types that append their own binary Avro encoding to a
+// byte slice, such as the types generated by the goavrogen command.  Marshal uses the MarshalAvro
+// method of such types when they also implement Unmarshaler and NamedType, and report the named
+// type of the Codec's schema.
+type Marshaler interface {
+	MarshalAvro(buf []byte) ([]byte, error)
+}
+
+// Unmarshaler is the interface implemented by types that decode their own binary Avro encoding,
+// returning the byte slice with the decoded bytes consumed.  Unmarshal uses the UnmarshalAvro
+// method of such types when they also implement Marshaler and NamedType, and report the named type
+// of the Codec's schema.
+type Unmarshaler interface {
+	UnmarshalAvro(buf []byte) ([]byte, error)
+}
+
+// NamedType is the interface implemented by types that represent one Avro named type, such as the
+// types generated by the goavrogen command.  AvroFullName returns the full name of the named type,
+// and AvroFingerprint returns the Rabin fingerprint of the Parsing Canonical Form of its schema, as
+// returned by the Fingerprint method of its Codec.  Values of other types, or of types reporting
+// another named type, are encoded and decoded using reflection rather than by their MarshalAvro
+// and UnmarshalAvro methods.
+type NamedType interface {
+	AvroFullName() string
+	AvroFingerprint() uint64
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	namedTypeType   = reflect.TypeOf((*NamedType)(nil)).Elem()
+)
+
+// reflectPlan encodes and decodes values of one Go type in accordance with one Avro schema.
+type reflectPlan struct {
+	encode func(buf []byte, v reflect.Value) ([]byte, error)
+	decode func(buf []byte, v reflect.Value) ([]byte, error)
+}
+
+// reflectPlanKey identifies the plan for a Go type and a codec while plans are being built.
+type reflectPlanKey struct {
+	w *Codec // codec for the writer schema of a resolving plan, and nil otherwise
+	c *Codec
+	t reflect.Type
+}
+
+// reflectPlans stores the plans already built while building the plan for a Go type.
+type reflectPlans struct {
+	built map[reflectPlanKey]*reflectPlan
+
+	// resolver resolves writer schemas to reader schemas for the plans of resolving codecs.
+	resolver *resolver
+
+	// strict requires each record field without a default value to have a matching struct field,
+	// so a missing struct field is reported when the plan is built rather than when encoding.
+	strict bool
+}
+
+func newReflectPlans(strict bool) *reflectPlans {
+	return &reflectPlans{
+		built:    make(map[reflectPlanKey]*reflectPlan),
+		resolver: &resolver{records: make(map[[2]*Codec]*func([]byte) (interface{}, []byte, error))},
+		strict:   strict,
+	}
+}
+
+// reflectPlan returns the plan for the Go type, building it when it has not yet been cached.
+func (c *Codec) reflectPlan(t reflect.Type) (*reflectPlan, error) {
+	if c.plans != nil {
+		if plan, ok := c.plans.Load(t); ok {
+			return plan.(*reflectPlan), nil
+		}
+	}
+	plan, err := buildCodecPlan(c, t, newReflectPlans(false))
+	if err != nil {
+		return nil, err
+	}
+	if c.plans != nil {
+		c.plans.Store(t, plan)
+	}
+	return plan, nil
+}
+
+// buildReflectPlan returns the plan for the Go type and the codec.  The plans store the plans
+// already built, so recursive types refer to the plan being built rather than building it again.
+func buildReflectPlan(c *Codec, t reflect.Type, plans *reflectPlans) (*reflectPlan, error) {
+	key := reflectPlanKey{c: c, t: t}
+	if plan, ok := plans.built[key]; ok {
+		return plan, nil
+	}
+	plan := new(reflectPlan)
+	plans.built[key] = plan // register before building, because a recursive type may refer back to it
+
+	var err error
+	switch {
+	case t.Kind() == reflect.Interface:
+		buildGenericPlan(plan, c)
+	case c.kind == "union" && c.underlying == nil:
+		err = buildUnionPlan(plan, c, t, plans)
+	case c.kind == "null":
+		plan.encode = func(buf []byte, _ reflect.Value) ([]byte, error) { return buf, nil }
+		plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			v.Set(reflect.Zero(v.Type()))
+			return buf, nil
+		}
+	case usesMarshaler(c, t):
+		buildMarshalerPlan(plan, t)
+	case c.underlying != nil && !(t.Kind() == reflect.Ptr && usesMarshaler(c, t.Elem())):
+		// NOTE: Check for logical types before unwrapping pointers, because the native Go type of
+		// some logical types is a pointer, such as *big.Rat for decimal.
+		buildLogicalPlan(plan, c, t)
+	case t.Kind() == reflect.Ptr:
+		err = buildPointerPlan(plan, c, t, plans)
+	default:
+		err = buildKindPlan(plan, c, t, plans)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// usesMarshaler returns true when values of the Go type encode themselves in accordance with the
+// codec's schema: the schema is a named type, for which goavrogen generates Go types, and pointers
+// to the Go type implement Marshaler, Unmarshaler, and NamedType, reporting the full name and the
+// fingerprint of the schema.  Unions and nulls enclosing such values are encoded by the plan rather
+// than by the value.
+func usesMarshaler(c *Codec, t reflect.Type) bool {
+	switch c.kind {
+	case "record", "enum", "fixed":
+	default:
+		return false
+	}
+	pt := reflect.PtrTo(t)
+	if !pt.Implements(marshalerType) || !pt.Implements(unmarshalerType) || !pt.Implements(namedTypeType) {
+		return false
+	}
+	nt := reflect.New(t).Interface().(NamedType)
+	return nt.AvroFullName() == c.typeName.fullName && nt.AvroFingerprint() == c.Fingerprint()
+}
+
+// buildMarshalerPlan sets the plan to encode and decode values using their MarshalAvro and
+// UnmarshalAvro methods.
+func buildMarshalerPlan(plan *reflectPlan, t reflect.Type) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if !v.CanAddr() {
+			pv := reflect.New(t)
+			pv.Elem().Set(v)
+			v = pv.Elem()
+		}
+		return v.Addr().Interface().(Marshaler).MarshalAvro(buf)
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalAvro(buf)
+	}
+}
+
+// buildGenericPlan sets the plan to encode and decode values using the generic representation of
+// the codec, for Go values of interface types.
+func buildGenericPlan(plan *reflectPlan, c *Codec) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		var datum interface{}
+		if !(v.Kind() == reflect.Interface && v.IsNil()) {
+			datum = v.Interface()
+		}
+		return c.binaryEncoder(buf, datum)
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, buf, err := c.binaryDecoder(buf)
+		if err != nil {
+			return buf, err
+		}
+		return buf, setNativeValue(v, value)
+	}
+}
+
+// buildLogicalPlan sets the plan to encode and decode the native Go values of a logical type.  The
+// Go type may also be a pointer to the native Go type, such as *time.Time for time.Time, or the
+// type pointed to by a native pointer, such as big.Rat for *big.Rat.
+func buildLogicalPlan(plan *reflectPlan, c *Codec, t reflect.Type) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		newBuf, err := c.binaryEncoder(buf, v.Interface())
+		if err == nil {
+			return newBuf, nil
+		}
+		var other reflect.Value
+		switch {
+		case t.Kind() == reflect.Ptr && !v.IsNil():
+			other = v.Elem()
+		case t.Kind() != reflect.Ptr:
+			other = reflect.New(t)
+			other.Elem().Set(v)
+		default:
+			return buf, err
+		}
+		if newBuf, otherErr := c.binaryEncoder(buf, other.Interface()); otherErr == nil {
+			return newBuf, nil
+		}
+		return buf, err
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, buf, err := c.binaryDecoder(buf)
+		if err != nil {
+			return buf, err
+		}
+		return buf, setLogicalValue(v, value)
+	}
+}
+
+// setLogicalValue stores the decoded native Go value of a logical type in the Go value, which may
+// also be a pointer to the native Go type, or the type pointed to by a native pointer.
+func setLogicalValue(v reflect.Value, value interface{}) error {
+	if value != nil {
+		t := v.Type()
+		nv := reflect.ValueOf(value)
+		switch {
+		case t.Kind() == reflect.Ptr && nv.Type().AssignableTo(t.Elem()):
+			pv := reflect.New(t.Elem())
+			pv.Elem().Set(nv)
+			v.Set(pv)
+			return nil
+		case nv.Kind() == reflect.Ptr && !nv.IsNil() && nv.Elem().Type().AssignableTo(t):
+			v.Set(nv.Elem())
+			return nil
+		}
+	}
+	return setNativeValue(v, value)
+}
+
+// setNativeValue stores the decoded value in the Go value, converting it to the Go type when it is
+// of a different type of the same kind.
+func setNativeValue(v reflect.Value, value interface{}) error {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	nv := reflect.ValueOf(value)
+	switch {
+	case nv.Type().AssignableTo(v.Type()):
+		v.Set(nv)
+	case nv.Kind() == v.Kind() && nv.Type().ConvertibleTo(v.Type()):
+		v.Set(nv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("cannot store %T in Go type %s", value, v.Type())
+	}
+	return nil
+}
+
+// buildPointerPlan sets the plan to encode and decode the values pointed to by Go pointers, for
+// schemas other than unions.
+func buildPointerPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	elem, err := buildReflectPlan(c, t.Elem(), plans)
+	if err != nil {
+		return err
+	}
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if v.IsNil() {
+			return buf, fmt.Errorf("cannot encode nil %s as %s: only unions with null may encode nil pointers", t, c.typeName)
+		}
+		return elem.encode(buf, v.Elem())
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return elem.decode(buf, v.Elem())
+	}
+	return nil
+}
+
+// buildUnionPlan sets the plan to encode and decode a union of null and one other type.  Go
+// pointers encode nil as null, and other Go types encode their value as the other type, and decode
+// null as their zero value.
+func buildUnionPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	nullIndex, valueIndex := -1, -1
+	for i, member := range c.members {
+		if member.kind == "null" {
+			nullIndex = i
+			continue
+		}
+		if valueIndex >= 0 {
+			if t.Kind() == reflect.Map {
+				buildGenericPlan(plan, c) // NOTE: generic union representation
+				return nil
+			}
+			return fmt.Errorf("cannot use Go type %s with Union of more than one type other than null; ought to use interface{}", t)
+		}
+		valueIndex = i
+	}
+	if valueIndex < 0 {
+		return fmt.Errorf("cannot use Go type %s with Union of only null", t)
+	}
+
+	elemType := t
+	if t.Kind() == reflect.Ptr && nullIndex >= 0 {
+		elemType = t.Elem()
+	}
+	elem, err := buildReflectPlan(c.members[valueIndex], elemType, plans)
+	if err != nil {
+		return err
+	}
+
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if elemType != t {
+			if v.IsNil() {
+				return AppendLong(buf, int64(nullIndex)), nil
+			}
+			v = v.Elem()
+		}
+		return elem.encode(AppendLong(buf, int64(valueIndex)), v)
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		index, buf, err := ReadLong(buf)
+		if err != nil {
+			return buf, fmt.Errorf("cannot decode Union: %s", err)
+		}
+		switch index {
+		case int64(nullIndex):
+			v.Set(reflect.Zero(t))
+			return buf, nil
+		case int64(valueIndex):
+			if elemType != t {
+				if v.IsNil() {
+					v.Set(reflect.New(elemType))
+				}
+				v = v.Elem()
+			}
+			if buf, err = elem.decode(buf, v); err != nil {
+				return buf, fmt.Errorf("cannot decode Union item %d: %s", index+1, err)
+			}
+			return buf, nil
+		default:
+			return buf, fmt.Errorf("cannot decode Union: index ought to be between 0 and %d; read index: %d", len(c.members)-1, index)
+		}
+	}
+	return nil
+}
+
+// buildKindPlan sets the plan to encode and decode Go values for the codec's Avro type.
+func buildKindPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	switch c.kind {
+	case "boolean":
+		if t.Kind() == reflect.Bool {
+			buildBooleanPlan(plan)
+			return nil
+		}
+	case "int":
+		if isIntKind(t.Kind()) {
+			buildIntegerPlan(plan, math.MinInt32, math.MaxInt32)
+			return nil
+		}
+	case "long":
+		if isIntKind(t.Kind()) {
+			buildIntegerPlan(plan, math.MinInt64, math.MaxInt64)
+			return nil
+		}
+	case "float":
+		if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+			buildFloatPlan(plan, c.kind)
+			return nil
+		}
+	case "double":
+		if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+			buildFloatPlan(plan, c.kind)
+			return nil
+		}
+	case "bytes", "string":
+		if t.Kind() == reflect.String || isByteSlice(t) {
+			buildBytesPlan(plan)
+			return nil
+		}
+	case "enum":
+		if t.Kind() == reflect.String {
+			buildEnumPlan(plan, c)
+			return nil
+		}
+	case "fixed":
+		if (t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 && t.Len() == c.size) || isByteSlice(t) {
+			buildFixedPlan(plan, c)
+			return nil
+		}
+	case "array":
+		if t.Kind() == reflect.Slice {
+			return buildArrayPlan(plan, c, t, plans)
+		}
+	case "map":
+		if t.Kind() == reflect.Map && t.Key().Kind() == reflect.String {
+			return buildMapPlan(plan, c, t, plans)
+		}
+	case "record":
+		switch {
+		case t.Kind() == reflect.Struct:
+			return buildStructPlan(plan, c, t, plans)
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface:
+			buildGenericPlan(plan, c) // NOTE: generic record representation
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot use Go type %s with Avro type %s", t, c.typeName)
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func buildBooleanPlan(plan *reflectPlan) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		return AppendBoolean(buf, v.Bool()), nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, buf, err := ReadBoolean(buf)
+		if err != nil {
+			return buf, err
+		}
+		v.SetBool(value)
+		return buf, nil
+	}
+}
+
+// buildIntegerPlan sets the plan to encode and decode Go integers as Avro int or long values
+// between min and max.
+func buildIntegerPlan(plan *reflectPlan, min, max int64) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		var value int64
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u := v.Uint()
+			if u > uint64(max) {
+				return buf, fmt.Errorf("provided Go %s would lose precision: %d", v.Type(), u)
+			}
+			value = int64(u)
+		default:
+			value = v.Int()
+		}
+		if value < min || value > max {
+			return buf, fmt.Errorf("provided Go %s would lose precision: %d", v.Type(), value)
+		}
+		return AppendLong(buf, value), nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, rest, err := ReadLong(buf)
+		if err != nil {
+			return buf, err
+		}
+		if value < min || value > max {
+			return buf, fmt.Errorf("decoded value out of range: %d", value)
+		}
+		if err = setInteger(v, value); err != nil {
+			return buf, err
+		}
+		return rest, nil
+	}
+}
+
+// setInteger stores the decoded integer in the Go integer value, unless it would overflow the Go
+// type.
+func setInteger(v reflect.Value, value int64) error {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value < 0 || v.OverflowUint(uint64(value)) {
+			return fmt.Errorf("decoded value would overflow Go %s: %d", v.Type(), value)
+		}
+		v.SetUint(uint64(value))
+	default:
+		if v.OverflowInt(value) {
+			return fmt.Errorf("decoded value would overflow Go %s: %d", v.Type(), value)
+		}
+		v.SetInt(value)
+	}
+	return nil
+}
+
+// buildFloatPlan sets the plan to encode and decode Go floating point numbers as Avro float or
+// double values.
+func buildFloatPlan(plan *reflectPlan, kind string) {
+	if kind == "float" {
+		plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			value := v.Float()
+			if float64(float32(value)) != value && !math.IsNaN(value) {
+				return buf, fmt.Errorf("provided Go %s would lose precision: %v", v.Type(), value)
+			}
+			return AppendFloat(buf, float32(value)), nil
+		}
+		plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			value, buf, err := ReadFloat(buf)
+			if err != nil {
+				return buf, err
+			}
+			v.SetFloat(float64(value))
+			return buf, nil
+		}
+		return
+	}
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		return AppendDouble(buf, v.Float()), nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, buf, err := ReadDouble(buf)
+		if err != nil {
+			return buf, err
+		}
+		v.SetFloat(value)
+		return buf, nil
+	}
+}
+
+// buildBytesPlan sets the plan to encode and decode Go strings and byte slices as Avro bytes or
+// string values.
+func buildBytesPlan(plan *reflectPlan) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if v.Kind() == reflect.String {
+			return AppendString(buf, v.String()), nil
+		}
+		return AppendBytes(buf, v.Bytes()), nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if v.Kind() == reflect.String {
+			value, buf, err := ReadString(buf)
+			if err != nil {
+				return buf, err
+			}
+			v.SetString(value)
+			return buf, nil
+		}
+		value, buf, err := ReadBytes(buf)
+		if err != nil {
+			return buf, err
+		}
+		// NOTE: Copy the bytes, so the decoded value does not refer to the caller's buffer.
+		v.SetBytes(append([]byte(nil), value...))
+		return buf, nil
+	}
+}
+
+// buildEnumPlan sets the plan to encode and decode Go strings as enum symbols.
+func buildEnumPlan(plan *reflectPlan, c *Codec) {
+	indexFromSymbol := make(map[string]int64, len(c.symbols))
+	for i, symbol := range c.symbols {
+		indexFromSymbol[symbol] = int64(i)
+	}
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		index, ok := indexFromSymbol[v.String()]
+		if !ok {
+			return buf, fmt.Errorf("cannot encode Enum %q: value ought to be member of symbols: %v; %q", c.typeName, c.symbols, v.String())
+		}
+		return AppendLong(buf, index), nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		index, rest, err := ReadLong(buf)
+		if err != nil {
+			return buf, fmt.Errorf("cannot decode Enum %q: %s", c.typeName, err)
+		}
+		if index < 0 || index >= int64(len(c.symbols)) {
+			return buf, fmt.Errorf("cannot decode Enum %q: index ought to be between 0 and %d; read index: %d", c.typeName, len(c.symbols)-1, index)
+		}
+		v.SetString(c.symbols[index])
+		return rest, nil
+	}
+}
+
+// buildFixedPlan sets the plan to encode and decode Go byte arrays and byte slices as fixed values.
+func buildFixedPlan(plan *reflectPlan, c *Codec) {
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if v.Len() != c.size {
+			return buf, fmt.Errorf("cannot encode Fixed %q: datum size ought to equal schema size: %d != %d", c.typeName, v.Len(), c.size)
+		}
+		if v.Kind() == reflect.Slice {
+			return append(buf, v.Bytes()...), nil
+		}
+		for i := 0; i < c.size; i++ {
+			buf = append(buf, byte(v.Index(i).Uint()))
+		}
+		return buf, nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, rest, err := ReadFixed(buf, c.size)
+		if err != nil {
+			return buf, fmt.Errorf("cannot decode Fixed %q: %s", c.typeName, err)
+		}
+		if v.Kind() == reflect.Slice {
+			v.SetBytes(append([]byte(nil), value...))
+		} else {
+			reflect.Copy(v, reflect.ValueOf(value))
+		}
+		return rest, nil
+	}
+}
+
+// buildArrayPlan sets the plan to encode and decode Go slices as arrays.
+func buildArrayPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	item, err := buildReflectPlan(c.items, t.Elem(), plans)
+	if err != nil {
+		return fmt.Errorf("Array items: %s", err)
+	}
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if n := v.Len(); n > 0 {
+			buf = AppendLong(buf, int64(n))
+			var err error
+			for i := 0; i < n; i++ {
+				if buf, err = item.encode(buf, v.Index(i)); err != nil {
+					return buf, fmt.Errorf("cannot encode Array item %d: %s", i+1, err)
+				}
+			}
+		}
+		return append(buf, 0), nil
+	}
+	plan.decode = sliceDecoder(t, item)
+	return nil
+}
+
+// buildMapPlan sets the plan to encode and decode Go maps with string keys as maps.
+func buildMapPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	value, err := buildReflectPlan(c.items, t.Elem(), plans)
+	if err != nil {
+		return fmt.Errorf("Map values: %s", err)
+	}
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if n := v.Len(); n > 0 {
+			buf = AppendLong(buf, int64(n))
+			var err error
+			for iter := v.MapRange(); iter.Next(); {
+				key := iter.Key().String()
+				buf = AppendString(buf, key)
+				if buf, err = value.encode(buf, iter.Value()); err != nil {
+					return buf, fmt.Errorf("cannot encode Map value for key %q: %s", key, err)
+				}
+			}
+		}
+		return append(buf, 0), nil
+	}
+	plan.decode = mapDecoder(t, value)
+	return nil
+}
+
+// sliceDecoder returns a function that decodes arrays into Go slices of the type, decoding each
+// item using the item plan.
+func sliceDecoder(t reflect.Type, item *reflectPlan) func([]byte, reflect.Value) ([]byte, error) {
+	return func(buf []byte, v reflect.Value) ([]byte, error) {
+		var s reflect.Value
+		buf, err := decodeBlocks(buf, func(blockCount int64, buf []byte) ([]byte, error) {
+			if blockCount <= 0 {
+				return buf, fmt.Errorf("cannot decode Array block count: ought to be positive; read count: %d", blockCount)
+			}
+			if !s.IsValid() {
+				// NOTE: Many encoders encode all array items in a single block, so allocate room
+				// for the items of the first block, but not more than the remaining buffer could
+				// hold, lest a corrupt block count cause an enormous allocation.
+				initialSize := blockCount
+				if initialSize > int64(len(buf)) {
+					initialSize = int64(len(buf))
+				}
+				s = reflect.MakeSlice(t, 0, int(initialSize))
+			}
+			for i := int64(0); i < blockCount; i++ {
+				s = reflect.Append(s, reflect.Zero(t.Elem()))
+				var err error
+				if buf, err = item.decode(buf, s.Index(s.Len()-1)); err != nil {
+					return buf, fmt.Errorf("cannot decode Array item %d: %s", s.Len(), err)
+				}
+			}
+			return buf, nil
+		})
+		if err != nil {
+			return buf, err
+		}
+		if !s.IsValid() {
+			s = reflect.MakeSlice(t, 0, 0)
+		}
+		v.Set(s)
+		return buf, nil
+	}
+}
+
+// mapDecoder returns a function that decodes maps into Go maps of the type, decoding each value
+// using the value plan.
+func mapDecoder(t reflect.Type, value *reflectPlan) func([]byte, reflect.Value) ([]byte, error) {
+	return func(buf []byte, v reflect.Value) ([]byte, error) {
+		m := reflect.MakeMap(t)
+		buf, err := decodeBlocks(buf, func(blockCount int64, buf []byte) ([]byte, error) {
+			for i := int64(0); i < blockCount; i++ {
+				var name string
+				var err error
+				if name, buf, err = ReadString(buf); err != nil {
+					return buf, fmt.Errorf("cannot decode Map key: %s", err)
+				}
+				key := reflect.New(t.Key()).Elem()
+				key.SetString(name)
+				item := reflect.New(t.Elem()).Elem()
+				if buf, err = value.decode(buf, item); err != nil {
+					return buf, fmt.Errorf("cannot decode Map value for key %q: %s", key.String(), err)
+				}
+				m.SetMapIndex(key, item)
+			}
+			return buf, nil
+		})
+		if err != nil {
+			return buf, err
+		}
+		v.Set(m)
+		return buf, nil
+	}
+}
+
+// decodeBlocks decodes the block counts of an array or map, calling fn with the count of each
+// block and the remaining buffer, from which fn ought to decode that many items.
+func decodeBlocks(buf []byte, fn func(blockCount int64, buf []byte) ([]byte, error)) ([]byte, error) {
+	for {
+		blockCount, rest, err := ReadBlockCount(buf)
+		if err != nil {
+			return buf, err
+		}
+		if blockCount == 0 {
+			return rest, nil
+		}
+		if buf, err = fn(blockCount, rest); err != nil {
+			return buf, err
+		}
+	}
+}
+
+// structFieldPlan maps one record field to a Go struct field.
+type structFieldPlan struct {
+	field *recordField
+	index []int        // index sequence of the struct field, as used by reflect.Value.FieldByIndex
+	plan  *reflectPlan // nil when the struct has no field for the record field
+}
+
+// buildStructPlan sets the plan to encode and decode Go structs as records.
+func buildStructPlan(plan *reflectPlan, c *Codec, t reflect.Type, plans *reflectPlans) error {
+	indexes := make(map[string][]int)
+	for _, sf := range structFields(t) {
+		indexes[sf.name] = sf.index
+	}
+	fields := make([]structFieldPlan, len(c.fields))
+	for i, field := range c.fields {
+		fields[i].field = field
+		index, ok := indexes[field.name]
+		if !ok {
+			if plans.strict && !field.hasDefault {
+				return fmt.Errorf("Record %q field %q ought to have matching field in Go type %s, or default value", c.typeName, field.name, t)
+			}
+			continue
+		}
+		fp, err := buildReflectPlan(field.codec, t.FieldByIndex(index).Type, plans)
+		if err != nil {
+			return fmt.Errorf("Record %q field %q: %s", c.typeName, field.name, err)
+		}
+		fields[i].index = index
+		fields[i].plan = fp
+	}
+
+	plan.encode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		var err error
+		for _, f := range fields {
+			var fv reflect.Value
+			if f.plan != nil {
+				fv = structField(v, f.index, false)
+			}
+			if !fv.IsValid() {
+				if !f.field.hasDefault {
+					return buf, fmt.Errorf("Record %q field value for %q was not specified", c.typeName, f.field.name)
+				}
+				if buf, err = f.field.codec.binaryEncoder(buf, f.field.nativeValue); err != nil {
+					return buf, fmt.Errorf("Record %q field value for %q does not match its schema: %s", c.typeName, f.field.name, err)
+				}
+				continue
+			}
+			if buf, err = f.plan.encode(buf, fv); err != nil {
+				return buf, fmt.Errorf("Record %q field value for %q does not match its schema: %s", c.typeName, f.field.name, err)
+			}
+		}
+		return buf, nil
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		var err error
+		for _, f := range fields {
+			if f.plan == nil {
+				buf, err = f.field.codec.binarySkipper(buf) // NOTE: no struct field; skip value
+			} else {
+				buf, err = f.plan.decode(buf, structField(v, f.index, true))
+			}
+			if err != nil {
+				return buf, fmt.Errorf("cannot decode Record %q field %q: %s", c.typeName, f.field.name, err)
+			}
+		}
+		return buf, nil
+	}
+	return nil
+}
+
+// structField returns the field of the struct value with the index sequence.  When the field is in
+// an embedded struct referred to by a nil pointer, it allocates the embedded struct when alloc is
+// true, and otherwise returns the zero Value.
+func structField(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// structFieldInfo describes the struct field used for a record field.
+type structFieldInfo struct {
+	name  string // name of the record field
+	index []int  // index sequence of the struct field, as used by reflect.Value.FieldByIndex
+}
+
+// structFields returns the struct fields used for record fields, in the order they are declared.
+// Fields are named by their avro tag, or by their Go name when they have no tag, and fields tagged
+// "-" are ignored.  The fields of embedded structs without a tag are included, unless the struct
+// has a field by the same name at a shallower depth.  As with encoding/json, names that are
+// ambiguous at the shallowest depth they appear are ignored.
+func structFields(t reflect.Type) []structFieldInfo {
+	var candidates []structFieldInfo
+
+	var walk func(t reflect.Type, prefix []int, visited map[reflect.Type]struct{})
+	walk = func(t reflect.Type, prefix []int, visited map[reflect.Type]struct{}) {
+		if _, ok := visited[t]; ok {
+			return // NOTE: struct embeds itself by pointer
+		}
+		visited[t] = struct{}{}
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("avro")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			index := append(append([]int(nil), prefix...), i)
+
+			if sf.Anonymous && name == "" {
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					if sf.PkgPath != "" {
+						continue // NOTE: cannot allocate unexported embedded pointer
+					}
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft, index, visited)
+					continue
+				}
+			}
+			if sf.PkgPath != "" {
+				continue // unexported
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			candidates = append(candidates, structFieldInfo{name: name, index: index})
+		}
+	}
+	walk(t, nil, make(map[reflect.Type]struct{}))
+
+	// NOTE: Use the shallowest field of each name, unless more than one field has that depth.
+	depths := make(map[string]int)
+	counts := make(map[string]int)
+	for _, candidate := range candidates {
+		depth, ok := depths[candidate.name]
+		switch {
+		case !ok || len(candidate.index) < depth:
+			depths[candidate.name] = len(candidate.index)
+			counts[candidate.name] = 1
+		case len(candidate.index) == depth:
+			counts[candidate.name]++
+		}
+	}
+	var fields []structFieldInfo
+	for _, candidate := range candidates {
+		if len(candidate.index) == depths[candidate.name] && counts[candidate.name] == 1 {
+			fields = append(fields, candidate)
+		}
+	}
+	return fields
+}
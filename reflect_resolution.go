@@ -0,0 +1,323 @@
+package goavro
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// buildCodecPlan returns the plan for the Go type and the codec.  The plan of a resolving codec
+// encodes values using the reader schema, and decodes data written with the writer schema.
+func buildCodecPlan(c *Codec, t reflect.Type, plans *reflectPlans) (*reflectPlan, error) {
+	if c.reader != nil {
+		return buildResolvingPlan(c.writer, c.reader, t, plans)
+	}
+	return buildReflectPlan(c, t, plans)
+}
+
+// buildResolvingPlan returns the plan for the Go type that encodes values using the reader codec,
+// and decodes data written with the writer codec, following the same resolution rules as
+// NewResolvingCodec.  Rather than decoding the generic representation of the reader schema, the
+// plan follows the writer encoding, skipping writer fields without a matching reader field or
+// struct field, and decoding the other values directly into the Go value.
+func buildResolvingPlan(w, r *Codec, t reflect.Type, plans *reflectPlans) (*reflectPlan, error) {
+	readerPlan, err := buildReflectPlan(r, t, plans)
+	if err != nil {
+		return nil, err
+	}
+	if w == r || w.CanonicalSchema() == r.CanonicalSchema() {
+		return readerPlan, nil // NOTE: schemas with the same canonical form encode data the same way
+	}
+	key := reflectPlanKey{w: w, c: r, t: t}
+	if plan, ok := plans.built[key]; ok {
+		return plan, nil
+	}
+
+	// NOTE: The resolver reports writer schemas that cannot be resolved to the reader schema, and
+	// its decoder returns values of the reader schema, for values stored without a plan of their
+	// own, such as promoted numbers and enum symbols.
+	decoder, err := plans.resolver.resolve(w, r)
+	if err != nil {
+		return nil, err
+	}
+	plan := &reflectPlan{encode: readerPlan.encode}
+	plans.built[key] = plan // register before building, because a recursive type may refer back to it
+
+	if w.underlying != nil {
+		w = w.underlying // NOTE: logical types do not affect how values are encoded
+	}
+	switch {
+	case t.Kind() == reflect.Interface:
+		plan.decode = resolvedValueDecoder(decoder)
+	case w.kind == "union":
+		buildWriterUnionPlan(plan, w, r, t, plans)
+	case r.kind == "union" && r.underlying == nil:
+		err = buildReaderUnionPlan(plan, w, r, t, decoder, plans)
+	case usesMarshaler(r, t):
+		// NOTE: Values that decode themselves only read data written with the reader schema, so
+		// encode the resolved value using the reader schema for them.
+		plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			value, newBuf, err := decoder(buf)
+			if err != nil {
+				return buf, err
+			}
+			data, err := r.binaryEncoder(nil, value)
+			if err != nil {
+				return buf, err
+			}
+			if _, err = readerPlan.decode(data, v); err != nil {
+				return buf, err
+			}
+			return newBuf, nil
+		}
+	case r.underlying != nil && !(t.Kind() == reflect.Ptr && usesMarshaler(r, t.Elem())):
+		plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			value, buf, err := decoder(buf)
+			if err != nil {
+				return buf, err
+			}
+			return buf, setLogicalValue(v, value)
+		}
+	case t.Kind() == reflect.Ptr:
+		var elem *reflectPlan
+		if elem, err = buildResolvingPlan(w, r, t.Elem(), plans); err == nil {
+			plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+				if v.IsNil() {
+					v.Set(reflect.New(t.Elem()))
+				}
+				return elem.decode(buf, v.Elem())
+			}
+		}
+	case r.kind == "record" && t.Kind() == reflect.Struct:
+		err = buildResolvingStructPlan(plan, w, r, t, plans)
+	case r.kind == "array" && t.Kind() == reflect.Slice:
+		var item *reflectPlan
+		if item, err = buildResolvingPlan(w.items, r.items, t.Elem(), plans); err != nil {
+			err = fmt.Errorf("Array items: %s", err)
+		}
+		plan.decode = sliceDecoder(t, item)
+	case r.kind == "map" && t.Kind() == reflect.Map:
+		var value *reflectPlan
+		if value, err = buildResolvingPlan(w.items, r.items, t.Elem(), plans); err != nil {
+			err = fmt.Errorf("Map values: %s", err)
+		}
+		plan.decode = mapDecoder(t, value)
+	case w.kind == r.kind && r.kind != "enum" && r.kind != "record":
+		plan.decode = readerPlan.decode // NOTE: same primitive or fixed type encodes data the same way
+	default:
+		plan.decode = resolvedValueDecoder(decoder)
+	}
+	if err != nil {
+		delete(plans.built, key)
+		return nil, err
+	}
+	return plan, nil
+}
+
+// resolvedValueDecoder returns a function that decodes values using the resolver's decoder, and
+// stores them in Go values.
+func resolvedValueDecoder(decoder func([]byte) (interface{}, []byte, error)) func([]byte, reflect.Value) ([]byte, error) {
+	return func(buf []byte, v reflect.Value) ([]byte, error) {
+		value, buf, err := decoder(buf)
+		if err != nil {
+			return buf, err
+		}
+		return buf, setResolvedValue(v, value)
+	}
+}
+
+// setResolvedValue stores the value of the reader schema in the Go value, converting numbers,
+// strings, and byte slices to the Go type as the plan of the reader schema would.
+func setResolvedValue(v reflect.Value, value interface{}) error {
+	switch x := value.(type) {
+	case int64:
+		if isIntKind(v.Kind()) {
+			return setInteger(v, x)
+		}
+	case float32:
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			v.SetFloat(float64(x))
+			return nil
+		}
+	case float64:
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			v.SetFloat(x)
+			return nil
+		}
+	case string:
+		if isByteSlice(v.Type()) {
+			v.SetBytes([]byte(x))
+			return nil
+		}
+	case []byte:
+		if v.Kind() == reflect.String {
+			v.SetString(string(x))
+			return nil
+		}
+	}
+	return setNativeValue(v, value)
+}
+
+// buildWriterUnionPlan sets the plan to decode a writer union by resolving the writer member at
+// the encoded index to the reader schema.  As with NewResolvingCodec, writer members that cannot be
+// resolved to the reader schema only cause an error when a value of that member is decoded.
+func buildWriterUnionPlan(plan *reflectPlan, w, r *Codec, t reflect.Type, plans *reflectPlans) {
+	members := make([]*reflectPlan, len(w.members))
+	errs := make([]error, len(w.members))
+	for i, member := range w.members {
+		members[i], errs[i] = buildResolvingPlan(member, r, t, plans)
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		index, rest, err := ReadLong(buf)
+		if err != nil {
+			return buf, fmt.Errorf("cannot decode Union: %s", err)
+		}
+		if index < 0 || index >= int64(len(members)) {
+			return buf, fmt.Errorf("cannot decode Union: index ought to be between 0 and %d; read index: %d", len(members)-1, index)
+		}
+		if errs[index] != nil {
+			return buf, fmt.Errorf("cannot resolve Union item %d: %s", index+1, errs[index])
+		}
+		if rest, err = members[index].decode(rest, v); err != nil {
+			return buf, err
+		}
+		return rest, nil
+	}
+}
+
+// buildReaderUnionPlan sets the plan to decode a non-union writer value as the member of the reader
+// union that NewResolvingCodec would resolve it to, storing it in the Go value as the plan of the
+// reader union would.
+func buildReaderUnionPlan(plan *reflectPlan, w, r *Codec, t reflect.Type, decoder func([]byte) (interface{}, []byte, error), plans *reflectPlans) error {
+	var hasNull bool
+	var values int
+	for _, member := range r.members {
+		if member.kind == "null" {
+			hasNull = true
+		} else {
+			values++
+		}
+	}
+	if values > 1 {
+		plan.decode = resolvedValueDecoder(decoder) // NOTE: generic union representation
+		return nil
+	}
+
+	member := readerUnionMember(w, r)
+	if member.kind == "null" {
+		plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+			v.Set(reflect.Zero(t))
+			return buf, nil
+		}
+		return nil
+	}
+	elemType := t
+	if t.Kind() == reflect.Ptr && hasNull {
+		elemType = t.Elem()
+	}
+	elem, err := buildResolvingPlan(w, member, elemType, plans)
+	if err != nil {
+		return err
+	}
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		if elemType != t {
+			if v.IsNil() {
+				v.Set(reflect.New(elemType))
+			}
+			v = v.Elem()
+		}
+		return elem.decode(buf, v)
+	}
+	return nil
+}
+
+// resolvingFieldPlan maps one writer record field to a Go struct field.
+type resolvingFieldPlan struct {
+	field *recordField // writer field
+	index []int        // index sequence of the struct field, as used by reflect.Value.FieldByIndex
+	plan  *reflectPlan // nil when the reader or the struct has no field for the writer field
+}
+
+// resolvingDefaultPlan maps one reader record field that is not in the writer schema to a Go
+// struct field.
+type resolvingDefaultPlan struct {
+	field *recordField // reader field
+	index []int        // index sequence of the struct field, as used by reflect.Value.FieldByIndex
+	plan  *reflectPlan // plan of the reader field
+	data  []byte       // binary encoding of the default value of the reader field
+}
+
+// buildResolvingStructPlan sets the plan to decode writer records into Go structs that follow the
+// reader schema.  Each writer field is skipped unless both the reader and the struct have a field
+// for it, and struct fields for reader fields absent from the writer schema are set to the default
+// value of the reader field.
+func buildResolvingStructPlan(plan *reflectPlan, w, r *Codec, t reflect.Type, plans *reflectPlans) error {
+	indexes := make(map[string][]int)
+	for _, sf := range structFields(t) {
+		indexes[sf.name] = sf.index
+	}
+
+	fields := make([]resolvingFieldPlan, len(w.fields))
+	found := make(map[string]struct{}, len(w.fields))
+	for i, rf := range matchFields(w, r) {
+		wf := w.fields[i]
+		fields[i].field = wf
+		if rf == nil {
+			continue
+		}
+		found[rf.name] = struct{}{}
+		index, ok := indexes[rf.name]
+		if !ok {
+			continue
+		}
+		fp, err := buildResolvingPlan(wf.codec, rf.codec, t.FieldByIndex(index).Type, plans)
+		if err != nil {
+			return fmt.Errorf("Record %q field %q: %s", r.typeName, rf.name, err)
+		}
+		fields[i].index = index
+		fields[i].plan = fp
+	}
+
+	var defaults []resolvingDefaultPlan
+	for _, rf := range r.fields {
+		if _, ok := found[rf.name]; ok {
+			continue
+		}
+		index, ok := indexes[rf.name]
+		if !ok {
+			continue
+		}
+		fp, err := buildReflectPlan(rf.codec, t.FieldByIndex(index).Type, plans)
+		if err != nil {
+			return fmt.Errorf("Record %q field %q: %s", r.typeName, rf.name, err)
+		}
+		// NOTE: The resolver already verified the reader field has a default value.
+		data, err := rf.codec.binaryEncoder(nil, rf.nativeValue)
+		if err != nil {
+			return fmt.Errorf("Record %q field %q default value: %s", r.typeName, rf.name, err)
+		}
+		defaults = append(defaults, resolvingDefaultPlan{field: rf, index: index, plan: fp, data: data})
+	}
+
+	plan.decode = func(buf []byte, v reflect.Value) ([]byte, error) {
+		var err error
+		for _, f := range fields {
+			if f.plan == nil {
+				buf, err = f.field.codec.binarySkipper(buf) // NOTE: no reader or struct field; skip value
+			} else {
+				buf, err = f.plan.decode(buf, structField(v, f.index, true))
+			}
+			if err != nil {
+				return buf, fmt.Errorf("cannot decode Record %q field %q: %s", w.typeName, f.field.name, err)
+			}
+		}
+		for _, d := range defaults {
+			// NOTE: Decode the default value for each struct, so structs do not share its
+			// slices and maps.
+			if _, err = d.plan.decode(d.data, structField(v, d.index, true)); err != nil {
+				return buf, fmt.Errorf("cannot decode Record %q field %q default value: %s", r.typeName, d.field.name, err)
+			}
+		}
+		return buf, nil
+	}
+	return nil
+}
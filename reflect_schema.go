@@ -0,0 +1,162 @@
+package goavro
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// SchemaOf returns the schema tree of an Avro schema for values of the Go type, which may be
+// converted to its JSON specification with SchemaJSON.  The schema follows the mapping between Go
+// values and Avro types used by Marshal and Unmarshal:
+//
+//   - structs become records named by their Go type name, whose fields are named by their avro
+//     tag, or by their Go name when they have no tag, as described for Marshal.
+//   - pointers become unions of null and the type they point to, and struct fields of pointer
+//     types have a null default value.
+//   - []byte becomes bytes, and [N]byte becomes a fixed named by its Go type name, or FixedN when
+//     the type is not named.
+//   - time.Time becomes a long annotated with the timestamp-micros logical type, and
+//     time.Duration becomes a long annotated with the time-micros logical type.
+//   - other slices become arrays, and maps with string keys become maps.
+//   - bool, int8, int16, int32, uint8, and uint16 become boolean or int, and the other integer
+//     types become long.  float32 becomes float, float64 becomes double, and string becomes
+//     string.
+//
+// Each Go type that becomes a named type is defined once, and referred to by name thereafter, so
+// recursive types, such as a struct with a field pointing to the same struct type, are supported.
+// It returns an error for types that cannot be represented, such as interface types, channels, and
+// maps whose keys are not strings.
+func SchemaOf(t reflect.Type) (Schema, error) {
+	ts := &typeSchemas{named: make(map[reflect.Type]Schema), names: make(map[string]reflect.Type)}
+	s, err := ts.schemaOf(t)
+	if err == nil {
+		_, err = SchemaJSON(s) // ensure the names derived from the Go types are valid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create schema for Go type %s: %s", t, err)
+	}
+	return s, nil
+}
+
+// typeSchemas stores the schemas of the named types created while creating the schema for a Go
+// type.
+type typeSchemas struct {
+	named map[reflect.Type]Schema // schema of each Go type that became a named type
+	names map[string]reflect.Type // Go type that became each named type
+}
+
+func (ts *typeSchemas) schemaOf(t reflect.Type) (Schema, error) {
+	if s, ok := ts.named[t]; ok {
+		return s, nil
+	}
+	switch t {
+	case timeType:
+		return Logical("timestamp-micros", Long(), nil), nil
+	case durationType:
+		return Logical("time-micros", Long(), nil), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Boolean(), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Int(), nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return Long(), nil
+	case reflect.Float32:
+		return Float(), nil
+	case reflect.Float64:
+		return Double(), nil
+	case reflect.String:
+		return String(), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Bytes(), nil
+		}
+		items, err := ts.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Array(items), nil
+	case reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("cannot create schema for Go array %s: only byte arrays are supported", t)
+		}
+		name := t.Name()
+		if name == "" {
+			name = fmt.Sprintf("Fixed%d", t.Len())
+		}
+		if err := ts.register(name, t); err != nil {
+			return nil, err
+		}
+		s := Fixed(name, t.Len())
+		ts.named[t] = s
+		return s, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot create schema for Go map %s: keys ought to be strings", t)
+		}
+		values, err := ts.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Map(values), nil
+	case reflect.Ptr:
+		elem, err := ts.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := elem.(*UnionSchema); ok {
+			return nil, fmt.Errorf("cannot create schema for Go type %s: union ought not to be member of another union", t)
+		}
+		return Nullable(elem), nil
+	case reflect.Struct:
+		return ts.recordOf(t)
+	default:
+		return nil, fmt.Errorf("cannot create schema for Go type %s", t)
+	}
+}
+
+// recordOf returns the record schema for the struct type.
+func (ts *typeSchemas) recordOf(t reflect.Type) (Schema, error) {
+	name := t.Name()
+	if name == "" {
+		return nil, errors.New("cannot create schema for anonymous struct: struct ought to be named type")
+	}
+	if err := ts.register(name, t); err != nil {
+		return nil, err
+	}
+	rs := &RecordSchema{Name: name}
+	ts.named[t] = rs // register before fields, which may refer back to this record
+
+	for _, sf := range structFields(t) {
+		ft := t.FieldByIndex(sf.index).Type
+		fs, err := ts.schemaOf(ft)
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %q: %s", name, sf.name, err)
+		}
+		field := &Field{Name: sf.name, Type: fs}
+		if ft.Kind() == reflect.Ptr {
+			field.HasDefault = true // NOTE: default value of null, the first member of the union
+		}
+		rs.Fields = append(rs.Fields, field)
+	}
+	return rs, nil
+}
+
+// register records that the Go type becomes the named type, and returns an error when another Go
+// type already became a named type by the same name.
+func (ts *typeSchemas) register(name string, t reflect.Type) error {
+	if other, ok := ts.names[name]; ok && other != t {
+		return fmt.Errorf("Go types %s and %s ought not to have the same name: %q", other, t, name)
+	}
+	ts.names[name] = t
+	return nil
+}
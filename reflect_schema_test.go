@@ -0,0 +1,149 @@
+package goavro_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karrick/goavro"
+)
+
+type schemaOfMD5 [16]byte
+
+type schemaOfBase struct {
+	Created time.Time `avro:"created"`
+}
+
+type schemaOfAccount struct {
+	schemaOfBase
+	ID       int64             `avro:"id"`
+	Name     string            `avro:"name"`
+	Email    *string           `avro:"email"`
+	Age      uint8             `avro:"age"`
+	Ratio    float32           `avro:"ratio"`
+	Score    float64           `avro:"score"`
+	Active   bool              `avro:"active"`
+	Blob     []byte            `avro:"blob"`
+	Hash     schemaOfMD5       `avro:"hash"`
+	Salt     [4]byte           `avro:"salt"`
+	Tags     []string          `avro:"tags"`
+	Limits   map[string]int32  `avro:"limits"`
+	Timeout  time.Duration     `avro:"timeout"`
+	Parent   *schemaOfAccount  `avro:"parent"`
+	Children []schemaOfAccount `avro:"children"`
+	Skipped  int               `avro:"-"`
+	internal int
+}
+
+func TestSchemaOf(t *testing.T) {
+	s, err := goavro.SchemaOf(reflect.TypeOf(schemaOfAccount{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := goavro.SchemaJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"record","name":"schemaOfAccount","fields":[` +
+		`{"name":"created","type":{"type":"long","logicalType":"timestamp-micros"}},` +
+		`{"name":"id","type":"long"},` +
+		`{"name":"name","type":"string"},` +
+		`{"name":"email","type":["null","string"],"default":null},` +
+		`{"name":"age","type":"int"},` +
+		`{"name":"ratio","type":"float"},` +
+		`{"name":"score","type":"double"},` +
+		`{"name":"active","type":"boolean"},` +
+		`{"name":"blob","type":"bytes"},` +
+		`{"name":"hash","type":{"type":"fixed","name":"schemaOfMD5","size":16}},` +
+		`{"name":"salt","type":{"type":"fixed","name":"Fixed4","size":4}},` +
+		`{"name":"tags","type":{"type":"array","items":"string"}},` +
+		`{"name":"limits","type":{"type":"map","values":"int"}},` +
+		`{"name":"timeout","type":{"type":"long","logicalType":"time-micros"}},` +
+		`{"name":"parent","type":["null","schemaOfAccount"],"default":null},` +
+		`{"name":"children","type":{"type":"array","items":"schemaOfAccount"}}]}`
+	if actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	// Values of the type ought to round trip through a codec for the schema.
+	codec, err := goavro.NewCodec(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "a@example.com"
+	account := schemaOfAccount{
+		schemaOfBase: schemaOfBase{Created: time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)},
+		ID:           1,
+		Email:        &email,
+		Blob:         []byte("b"),
+		Tags:         []string{},
+		Limits:       map[string]int32{"x": 1},
+		Timeout:      time.Second,
+		Parent:       &schemaOfAccount{Name: "parent", Blob: []byte{}, Tags: []string{}, Limits: map[string]int32{}, Children: []schemaOfAccount{}, schemaOfBase: schemaOfBase{Created: time.Unix(0, 0).UTC()}},
+		Children:     []schemaOfAccount{},
+	}
+	buf, err := codec.Marshal(nil, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded schemaOfAccount
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	again, err := codec.Marshal(nil, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, buf) {
+		t.Errorf("Actual: %v; Expected: %v", again, buf)
+	}
+}
+
+func TestSchemaOfPrimitive(t *testing.T) {
+	s, err := goavro.SchemaOf(reflect.TypeOf(map[string]*int16{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := goavro.SchemaJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"type":"map","values":["null","int"]}`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestSchemaOfInvalid(t *testing.T) {
+	type withInterface struct {
+		Any interface{}
+	}
+	type withNestedPointer struct {
+		P **int
+	}
+	type withIntKeys struct {
+		M map[int]string
+	}
+	type withBadName struct {
+		F int `avro:"not-valid"`
+	}
+	cases := []struct {
+		t            reflect.Type
+		errorMessage string
+	}{
+		{reflect.TypeOf(struct{ A int }{}), "struct ought to be named type"},
+		{reflect.TypeOf(withInterface{}), `Record "withInterface" field "Any": cannot create schema for Go type interface {}`},
+		{reflect.TypeOf(withNestedPointer{}), "union ought not to be member of another union"},
+		{reflect.TypeOf(withIntKeys{}), "keys ought to be strings"},
+		{reflect.TypeOf([3]int{}), "only byte arrays are supported"},
+		{reflect.TypeOf(withBadName{}), `Record "withBadName" field 1 ought to be valid`},
+		{reflect.TypeOf(make(chan int)), "cannot create schema for Go type chan int"},
+	}
+	for _, c := range cases {
+		_, err := goavro.SchemaOf(c.t)
+		if err == nil || !strings.Contains(err.Error(), c.errorMessage) {
+			t.Errorf("Actual: %v; Expected: %s", err, c.errorMessage)
+		}
+	}
+}
@@ -0,0 +1,716 @@
+package goavro_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karrick/goavro"
+)
+
+type reflectAudit struct {
+	Created time.Time `avro:"created"`
+}
+
+type reflectAddress struct {
+	City string `avro:"city"`
+}
+
+type reflectUser struct {
+	reflectAudit
+	ID       int64             `avro:"id"`
+	Name     string            `avro:"name"`
+	Email    *string           `avro:"email"`
+	Age      int32             `avro:"age"`
+	Score    float64           `avro:"score"`
+	Active   bool              `avro:"active"`
+	Kind     string            `avro:"kind"`
+	Hash     [4]byte           `avro:"hash"`
+	Tags     []string          `avro:"tags"`
+	Attrs    map[string]int    `avro:"attrs"`
+	Home     reflectAddress    `avro:"home"`
+	Work     *reflectAddress   `avro:"work"`
+	Extra    interface{}       `avro:"extra"`
+	Ignored  string            `avro:"-"`
+	Untagged map[string][]byte // matched by Go field name
+}
+
+const reflectUserSchema = `{"type":"record","name":"User","fields":[
+	{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},
+	{"name":"id","type":"long"},
+	{"name":"name","type":"string"},
+	{"name":"email","type":["null","string"]},
+	{"name":"age","type":"int"},
+	{"name":"score","type":"double"},
+	{"name":"active","type":"boolean"},
+	{"name":"kind","type":{"type":"enum","name":"Kind","symbols":["ADMIN","GUEST"]}},
+	{"name":"hash","type":{"type":"fixed","name":"Hash","size":4}},
+	{"name":"tags","type":{"type":"array","items":"string"}},
+	{"name":"attrs","type":{"type":"map","values":"int"}},
+	{"name":"home","type":{"type":"record","name":"Address","fields":[{"name":"city","type":"string"}]}},
+	{"name":"work","type":["null","Address"]},
+	{"name":"extra","type":["null","int","string"]},
+	{"name":"Untagged","type":{"type":"map","values":"bytes"}},
+	{"name":"note","type":"string","default":"none"}
+]}`
+
+func TestReflectMarshalUnmarshal(t *testing.T) {
+	codec, err := goavro.NewCodec(reflectUserSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "ann@example.com"
+	created := time.Date(2017, 6, 1, 12, 30, 0, 0, time.UTC)
+	user := reflectUser{
+		reflectAudit: reflectAudit{Created: created},
+		ID:           13,
+		Name:         "ann",
+		Email:        &email,
+		Age:          42,
+		Score:        3.5,
+		Active:       true,
+		Kind:         "GUEST",
+		Hash:         [4]byte{1, 2, 3, 4},
+		Tags:         []string{"a", "b"},
+		Attrs:        map[string]int{"x": 1},
+		Home:         reflectAddress{City: "Paris"},
+		Extra:        goavro.Union("string", "more"),
+		Ignored:      "ignored",
+		Untagged:     map[string][]byte{"k": []byte("v")},
+	}
+
+	buf, err := codec.Marshal(nil, &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Marshal ought to produce the same encoding as BinaryEncode of the generic representation.
+	expected, err := codec.BinaryEncode(nil, map[string]interface{}{
+		"created":  created,
+		"id":       13,
+		"name":     "ann",
+		"email":    goavro.Union("string", email),
+		"age":      42,
+		"score":    3.5,
+		"active":   true,
+		"kind":     "GUEST",
+		"hash":     []byte{1, 2, 3, 4},
+		"tags":     []interface{}{"a", "b"},
+		"attrs":    map[string]interface{}{"x": 1},
+		"home":     map[string]interface{}{"city": "Paris"},
+		"work":     nil,
+		"extra":    goavro.Union("string", "more"),
+		"Untagged": map[string]interface{}{"k": []byte("v")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %v; Expected: %v", buf, expected)
+	}
+
+	var decoded reflectUser
+	rest, err := codec.Unmarshal(append(buf, 0xff), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	user.Ignored = ""
+	if !decoded.Created.Equal(user.Created) {
+		t.Errorf("Actual: %v; Expected: %v", decoded.Created, user.Created)
+	}
+	decoded.Created = user.Created
+	if !reflect.DeepEqual(decoded, user) {
+		t.Errorf("Actual: %#v; Expected: %#v", decoded, user)
+	}
+}
+
+type reflectNode struct {
+	Value int64        `avro:"value"`
+	Next  *reflectNode `avro:"next"`
+}
+
+func TestReflectRecursive(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"LongList","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","LongList"],"default":null}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := &reflectNode{Value: 1, Next: &reflectNode{Value: 2, Next: &reflectNode{Value: 3}}}
+	buf, err := codec.Marshal(nil, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{2, 2, 4, 2, 6, 0}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	var decoded *reflectNode
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, list) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, list)
+	}
+}
+
+func TestReflectPrimitives(t *testing.T) {
+	type myString string
+
+	codec, err := goavro.NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.Marshal(nil, uint8(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var i8 int8
+	if _, err = codec.Unmarshal(buf, &i8); err == nil || !strings.Contains(err.Error(), "overflow") {
+		t.Errorf("Actual: %v; Expected: %s", err, "overflow")
+	}
+	var u16 uint16
+	if _, err = codec.Unmarshal(buf, &u16); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := u16, uint16(200); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if _, err = codec.Marshal(nil, int64(1<<40)); err == nil || !strings.Contains(err.Error(), "lose precision") {
+		t.Errorf("Actual: %v; Expected: %s", err, "lose precision")
+	}
+
+	codec, err = goavro.NewCodec(`"bytes"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf, err = codec.Marshal(nil, myString("hi")); err != nil {
+		t.Fatal(err)
+	}
+	var s myString
+	if _, err = codec.Unmarshal(buf, &s); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := s, myString("hi"); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	codec, err = goavro.NewCodec(`"float"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf, err = codec.Marshal(nil, 3.5); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{0, 0, 0x60, 0x40}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	var f float32
+	if _, err = codec.Unmarshal(buf, &f); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := f, float32(3.5); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestReflectNullableValue(t *testing.T) {
+	type record struct {
+		Count int `avro:"count"`
+	}
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[{"name":"count","type":["null","int"]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.Marshal(nil, record{Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{2, 6}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	decoded := record{Count: 3}
+	if _, err = codec.Unmarshal([]byte{0}, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := decoded.Count, 0; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestReflectEmbeddedPointer(t *testing.T) {
+	type Inner struct {
+		City string `avro:"city"`
+	}
+	type outer struct {
+		*Inner
+		Name string `avro:"name"`
+	}
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[{"name":"name","type":"string"},{"name":"city","type":"string","default":"nowhere"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.Marshal(nil, outer{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte("\x02a\x0enowhere"); !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	var decoded outer
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Inner == nil || decoded.City != "nowhere" {
+		t.Errorf("Actual: %#v; Expected: %v", decoded.Inner, "nowhere")
+	}
+}
+
+func TestReflectResolvingCodec(t *testing.T) {
+	type reader struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	codec, err := goavro.NewResolvingCodec(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"c","type":"string"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bee"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded reader
+	rest, err := codec.Unmarshal([]byte("\x06\x02c\xff"), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := decoded, (reader{A: 3, B: "bee"}); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestReflectResolvingCodecGeneric(t *testing.T) {
+	codec, err := goavro.NewResolvingCodec(`"float"`, `"double"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value interface{}
+	if _, err = codec.Unmarshal(goavro.AppendFloat(nil, 1.5), &value); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, 1.5; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+
+	writerSchema := `{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"c","type":"string"}]}`
+	readerSchema := `{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bee"}]}`
+	resolving, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	projected, err := goavro.NewProjectedCodec(writerSchema, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		codec    *goavro.Codec
+		expected map[string]interface{}
+	}{
+		{resolving, map[string]interface{}{"a": int64(3), "b": "bee"}},
+		{projected, map[string]interface{}{"a": int32(3)}},
+	}
+	for _, c := range cases {
+		var m map[string]interface{}
+		rest, err := c.codec.Unmarshal([]byte("\x06\x02c\xff"), &m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+		if !reflect.DeepEqual(m, c.expected) {
+			t.Errorf("Actual: %v; Expected: %v", m, c.expected)
+		}
+
+		var iface interface{}
+		if _, err = c.codec.Unmarshal([]byte("\x06\x02c"), &iface); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(iface, c.expected) {
+			t.Errorf("Actual: %v; Expected: %v", iface, c.expected)
+		}
+	}
+}
+
+func TestReflectResolvingCodecNested(t *testing.T) {
+	writerSchema := `{"type":"record","name":"r","fields":[
+		{"name":"id","type":"int"},
+		{"name":"blob","type":"bytes"},
+		{"name":"color","type":{"type":"enum","name":"color","symbols":["RED","GREEN","BLUE"]}},
+		{"name":"score","type":["null","float"]},
+		{"name":"parts","type":{"type":"array","items":{"type":"record","name":"part","fields":[{"name":"n","type":"int"},{"name":"x","type":"string"}]}}},
+		{"name":"counts","type":{"type":"map","values":"int"}},
+		{"name":"next","type":"int"}
+	]}`
+	readerSchema := `{"type":"record","name":"r","fields":[
+		{"name":"id","type":"long"},
+		{"name":"color","type":{"type":"enum","name":"color","symbols":["GREEN","OTHER"],"default":"OTHER"}},
+		{"name":"score","type":["null","double"]},
+		{"name":"parts","type":{"type":"array","items":{"type":"record","name":"part","fields":[{"name":"n","type":"long"}]}}},
+		{"name":"counts","type":{"type":"map","values":"double"}},
+		{"name":"next","type":["null","long"]},
+		{"name":"tags","type":{"type":"array","items":"string"},"default":["a","b"]}
+	]}`
+	type part struct {
+		N int `avro:"n"`
+	}
+	type reader struct {
+		ID     int32              `avro:"id"`
+		Color  string             `avro:"color"`
+		Score  *float64           `avro:"score"`
+		Parts  []part             `avro:"parts"`
+		Counts map[string]float32 `avro:"counts"`
+		Next   *int64             `avro:"next"`
+		Tags   []string           `avro:"tags"`
+	}
+
+	writer, err := goavro.NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryEncode(nil, map[string]interface{}{
+		"id":     3,
+		"blob":   []byte("ignored"),
+		"color":  "BLUE",
+		"score":  goavro.Union("float", float32(1.5)),
+		"parts":  []interface{}{map[string]interface{}{"n": 4, "x": "ignored"}},
+		"counts": map[string]interface{}{"k": 5},
+		"next":   6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded reader
+	rest, err := codec.Unmarshal(append(buf, 0xff), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	score, next := 1.5, int64(6)
+	expected := reader{ID: 3, Color: "OTHER", Score: &score, Parts: []part{{N: 4}}, Counts: map[string]float32{"k": 5}, Next: &next, Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Actual: %+v; Expected: %+v", decoded, expected)
+	}
+
+	// NOTE: Each struct receives its own copy of the default value.
+	decoded.Tags[0] = "modified"
+	var again reader
+	if _, err = codec.Unmarshal(buf, &again); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := again.Tags, []string{"a", "b"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	// NOTE: Writer union members that cannot be resolved only cause an error when decoded.
+	codec, err = goavro.NewResolvingCodec(`["int","string"]`, `"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value int
+	if _, err = codec.Unmarshal([]byte{0, 14}, &value); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, 7; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if _, err = codec.Unmarshal([]byte{2, 2, 'a'}, &value); err == nil || !strings.Contains(err.Error(), "cannot resolve Union item 2") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot resolve Union item 2")
+	}
+}
+
+func TestReflectErrors(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"b","type":["int","string"]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type missing struct {
+		B interface{} `avro:"b"`
+	}
+	buf, err := codec.Marshal([]byte("prefix"), missing{B: nil})
+	if err == nil || !strings.Contains(err.Error(), `Record "r" field value for "a" was not specified`) {
+		t.Errorf("Actual: %v; Expected: %s", err, "not specified")
+	}
+	if actual, expected := buf, []byte("prefix"); !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	type ambiguous struct {
+		A int    `avro:"a"`
+		B string `avro:"b"`
+	}
+	if _, err = codec.Marshal(nil, ambiguous{}); err == nil || !strings.Contains(err.Error(), "ought to use interface{}") {
+		t.Errorf("Actual: %v; Expected: %s", err, "ought to use interface{}")
+	}
+
+	type mismatch struct {
+		A string `avro:"a"`
+	}
+	if _, err = codec.Marshal(nil, mismatch{}); err == nil || !strings.Contains(err.Error(), "cannot use Go type string with Avro type int") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot use Go type")
+	}
+
+	var decoded ambiguous
+	if _, err = codec.Unmarshal(nil, decoded); err == nil || !strings.Contains(err.Error(), "ought to be non-nil pointer") {
+		t.Errorf("Actual: %v; Expected: %s", err, "ought to be non-nil pointer")
+	}
+
+	type short struct {
+		A int `avro:"a"`
+	}
+	encoded := []byte{2, 0x82}
+	rest, err := codec.Unmarshal(encoded, &short{})
+	if err == nil || !strings.Contains(err.Error(), "short buffer") {
+		t.Errorf("Actual: %v; Expected: %s", err, "short buffer")
+	}
+	if !bytes.Equal(rest, encoded) {
+		t.Errorf("Actual: %v; Expected: %v", rest, encoded)
+	}
+}
+
+func TestReflectFloatPrecision(t *testing.T) {
+	codec, err := goavro.NewCodec(`"float"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []float64{1.5, -0.25, math.Inf(1), math.NaN()} {
+		if _, err = codec.Marshal(nil, value); err != nil {
+			t.Errorf("Actual: %v; Expected: %v", err, nil)
+		}
+	}
+	for _, value := range []float64{0.1, math.MaxFloat64} {
+		buf, err := codec.Marshal([]byte("prefix"), value)
+		if err == nil || !strings.Contains(err.Error(), "would lose precision") {
+			t.Errorf("Actual: %v; Expected: %s", err, "would lose precision")
+		}
+		if actual, expected := buf, []byte("prefix"); !bytes.Equal(actual, expected) {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+	}
+	if _, err = codec.Marshal(nil, float32(0.1)); err != nil {
+		t.Errorf("Actual: %v; Expected: %v", err, nil)
+	}
+}
+
+func TestReflectArrayBlockCountOverflow(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"long"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: Block count of math.MinInt64, which remains negative when negated, followed by a
+	// block size of 0.
+	encoded := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x00}
+	var decoded []int64
+	rest, err := codec.Unmarshal(encoded, &decoded)
+	if err == nil || !strings.Contains(err.Error(), "block count") {
+		t.Errorf("Actual: %v; Expected: %s", err, "block count")
+	}
+	if !bytes.Equal(rest, encoded) {
+		t.Errorf("Actual: %v; Expected: %v", rest, encoded)
+	}
+}
+
+func TestReflectLogicalPointers(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}},
+		{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"updated","type":["null",{"type":"long","logicalType":"timestamp-millis"}]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type record struct {
+		Amount  *big.Rat   `avro:"amount"`
+		Created *time.Time `avro:"created"`
+		Updated *time.Time `avro:"updated"`
+	}
+	created := time.Date(2020, time.January, 2, 3, 4, 5, 6e6, time.UTC)
+	value := record{Amount: big.NewRat(12345, 100), Created: &created}
+	buf, err := codec.Marshal(nil, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := codec.BinaryEncode(nil, map[string]interface{}{"amount": big.NewRat(12345, 100), "created": created, "updated": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %v; Expected: %v", buf, expected)
+	}
+
+	var decoded record
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Amount == nil || decoded.Amount.Cmp(value.Amount) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", decoded.Amount, value.Amount)
+	}
+	if decoded.Created == nil || !decoded.Created.Equal(created) {
+		t.Errorf("Actual: %v; Expected: %v", decoded.Created, created)
+	}
+	if decoded.Updated != nil {
+		t.Errorf("Actual: %v; Expected: %v", decoded.Updated, nil)
+	}
+
+	// NOTE: The type pointed to by the native pointer of a logical type may also be used.
+	type byValue struct {
+		Amount  big.Rat    `avro:"amount"`
+		Created time.Time  `avro:"created"`
+		Updated *time.Time `avro:"updated"`
+	}
+	if buf, err = codec.Marshal(nil, byValue{Amount: *big.NewRat(12345, 100), Created: created}); err != nil {
+		t.Fatal(err)
+	}
+	var decodedByValue byValue
+	if _, err = codec.Unmarshal(buf, &decodedByValue); err != nil {
+		t.Fatal(err)
+	}
+	if decodedByValue.Amount.Cmp(value.Amount) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", &decodedByValue.Amount, value.Amount)
+	}
+
+	if _, err = codec.Marshal(nil, record{Created: &created}); err == nil || !strings.Contains(err.Error(), "decimal") {
+		t.Errorf("Actual: %v; Expected: %s", err, "decimal")
+	}
+}
+
+const reflectPointSchema = `{"type":"record","name":"Point","fields":[{"name":"x","type":"int"},{"name":"y","type":"int"}]}`
+
+var reflectPointFingerprint = func() uint64 {
+	codec, err := goavro.NewCodec(reflectPointSchema)
+	if err != nil {
+		panic(err)
+	}
+	return codec.Fingerprint()
+}()
+
+// reflectPoint encodes itself, as the types generated by goavrogen do.
+type reflectPoint struct {
+	X, Y int32
+}
+
+func (p *reflectPoint) AvroFullName() string { return "Point" }
+
+func (p *reflectPoint) AvroFingerprint() uint64 { return reflectPointFingerprint }
+
+func (p *reflectPoint) MarshalAvro(buf []byte) ([]byte, error) {
+	return goavro.AppendInt(goavro.AppendInt(buf, p.X), p.Y), nil
+}
+
+func (p *reflectPoint) UnmarshalAvro(buf []byte) ([]byte, error) {
+	b := buf
+	var err error
+	if p.X, b, err = goavro.ReadInt(b); err != nil {
+		return buf, err
+	}
+	if p.Y, b, err = goavro.ReadInt(b); err != nil {
+		return buf, err
+	}
+	return b, nil
+}
+
+func TestReflectMarshaler(t *testing.T) {
+	type shape struct {
+		Points []reflectPoint `avro:"points"`
+	}
+	codec, err := goavro.NewCodec(`{"type":"record","name":"Shape","fields":[{"name":"points","type":{"type":"array","items":` + reflectPointSchema + `}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := shape{Points: []reflectPoint{{1, 2}, {3, 4}}}
+	buf, err := codec.Marshal(nil, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{4, 2, 4, 6, 8, 0}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	var decoded shape
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, s) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, s)
+	}
+}
+
+func TestReflectMarshalerInUnion(t *testing.T) {
+	type shape struct {
+		Center *reflectPoint `avro:"center"`
+		Corner reflectPoint  `avro:"corner"`
+	}
+	codec, err := goavro.NewCodec(`{"type":"record","name":"Shape","fields":[{"name":"center","type":["null",` + reflectPointSchema + `]},{"name":"corner","type":["null","Point"]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := shape{Center: &reflectPoint{1, 2}, Corner: reflectPoint{3, 4}}
+	buf, err := codec.Marshal(nil, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: The union index ought to precede the encoding of each point.
+	if actual, expected := buf, []byte{2, 2, 4, 2, 6, 8}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if _, _, err = codec.BinaryDecode(buf); err != nil {
+		t.Error(err)
+	}
+	var decoded shape
+	if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, s) {
+		t.Errorf("Actual: %v; Expected: %v", decoded, s)
+	}
+}
+
+func TestReflectMarshalerMismatchedSchema(t *testing.T) {
+	// NOTE: Each schema differs from the named type reported by reflectPoint, by its name or by
+	// its fields, so the point is encoded using reflection, in the order of the schema fields.
+	for _, schema := range []string{
+		`{"type":"record","name":"Other","fields":[{"name":"Y","type":"int"},{"name":"X","type":"int"}]}`,
+		`{"type":"record","name":"Point","fields":[{"name":"Y","type":"int"},{"name":"X","type":"int"}]}`,
+	} {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := reflectPoint{1, 2}
+		buf, err := codec.Marshal(nil, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := buf, []byte{4, 2}; !bytes.Equal(actual, expected) {
+			t.Errorf("schema: %s; Actual: %v; Expected: %v", schema, actual, expected)
+		}
+		var decoded reflectPoint
+		if _, err = codec.Unmarshal(buf, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded != p {
+			t.Errorf("schema: %s; Actual: %v; Expected: %v", schema, decoded, p)
+		}
+	}
+}
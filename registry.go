@@ -0,0 +1,138 @@
+package goavro
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SchemaRegistry is the interface implemented by types that store schemas by subject, and assign
+// each distinct schema an ID, such as the Confluent Schema Registry.
+type SchemaRegistry interface {
+	// Register stores the schema under the specified subject, and returns the ID of the schema.
+	// Registering a schema that the registry already stores returns the existing ID.
+	Register(subject, schema string) (int, error)
+
+	// SchemaByID returns the schema having the specified ID.
+	SchemaByID(id int) (string, error)
+}
+
+// MemorySchemaRegistry is a SchemaRegistry that stores schemas in memory.  Schemas that differ only
+// by documentation or by JSON formatting are assigned the same ID, while schemas that differ by
+// attributes that Parsing Canonical Form strips, such as logical types, defaults, and aliases, are
+// assigned distinct IDs.  It is safe for concurrent use, and is intended for tests and for programs
+// that do not share schemas with other programs.
+type MemorySchemaRegistry struct {
+	lock        sync.RWMutex
+	schemas     []string            // schema of each ID, at index ID-1
+	idFromForm  map[string]int      // ID of each registry form
+	subjectsIDs map[string][]int    // IDs of each version of a subject, in version order
+	subjectForm map[string]struct{} // subject and registry form pairs already registered
+}
+
+// NewMemorySchemaRegistry returns a new empty MemorySchemaRegistry.
+func NewMemorySchemaRegistry() *MemorySchemaRegistry {
+	return &MemorySchemaRegistry{
+		idFromForm:  make(map[string]int),
+		subjectsIDs: make(map[string][]int),
+		subjectForm: make(map[string]struct{}),
+	}
+}
+
+// Register stores the schema under the specified subject, and returns the ID of the schema.
+func (msr *MemorySchemaRegistry) Register(subject, schema string) (int, error) {
+	if subject == "" {
+		return 0, fmt.Errorf("cannot register schema without subject")
+	}
+	c, err := NewCodec(schema)
+	if err != nil {
+		return 0, fmt.Errorf("cannot register invalid schema: %s", err)
+	}
+	form, err := registryForm(c.Schema())
+	if err != nil {
+		return 0, fmt.Errorf("cannot register invalid schema: %s", err)
+	}
+
+	msr.lock.Lock()
+	defer msr.lock.Unlock()
+
+	id, ok := msr.idFromForm[form]
+	if !ok {
+		msr.schemas = append(msr.schemas, schema)
+		id = len(msr.schemas)
+		msr.idFromForm[form] = id
+	}
+	key := subject + "\x00" + form
+	if _, ok := msr.subjectForm[key]; !ok {
+		msr.subjectForm[key] = struct{}{}
+		msr.subjectsIDs[subject] = append(msr.subjectsIDs[subject], id)
+	}
+	return id, nil
+}
+
+// registryForm returns the JSON specification of the schema without its documentation, which
+// identifies the schema in the registry.  Unlike Parsing Canonical Form, it keeps the logical
+// types, defaults, aliases, and other attributes that change how data is encoded or resolved.
+func registryForm(s Schema) (string, error) {
+	clearSchemaDocs(s, make(map[Schema]struct{}))
+	return SchemaJSON(s)
+}
+
+// clearSchemaDocs removes the documentation from the schema tree.  The visited map stores the named
+// schemas already cleared, so recursive types are cleared once.
+func clearSchemaDocs(s Schema, visited map[Schema]struct{}) {
+	switch st := s.(type) {
+	case *ArraySchema:
+		clearSchemaDocs(st.Items, visited)
+	case *MapSchema:
+		clearSchemaDocs(st.Values, visited)
+	case *LogicalSchema:
+		clearSchemaDocs(st.Underlying, visited)
+	case *UnionSchema:
+		for _, member := range st.Members {
+			clearSchemaDocs(member, visited)
+		}
+	case *EnumSchema:
+		st.Doc = ""
+	case *RecordSchema:
+		if _, ok := visited[st]; ok {
+			return
+		}
+		visited[st] = struct{}{}
+		st.Doc = ""
+		for _, field := range st.Fields {
+			field.Doc = ""
+			clearSchemaDocs(field.Type, visited)
+		}
+	}
+}
+
+// SchemaByID returns the schema having the specified ID.
+func (msr *MemorySchemaRegistry) SchemaByID(id int) (string, error) {
+	msr.lock.RLock()
+	defer msr.lock.RUnlock()
+	if id < 1 || id > len(msr.schemas) {
+		return "", fmt.Errorf("cannot find schema with ID: %d", id)
+	}
+	return msr.schemas[id-1], nil
+}
+
+// Subjects returns the sorted list of subjects having one or more registered schemas.
+func (msr *MemorySchemaRegistry) Subjects() []string {
+	msr.lock.RLock()
+	defer msr.lock.RUnlock()
+	subjects := make([]string, 0, len(msr.subjectsIDs))
+	for subject := range msr.subjectsIDs {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// Versions returns the schema IDs registered under the specified subject, in the order they were
+// registered, such that the ID at index i is version i+1 of the subject.
+func (msr *MemorySchemaRegistry) Versions(subject string) []int {
+	msr.lock.RLock()
+	defer msr.lock.RUnlock()
+	return append([]int(nil), msr.subjectsIDs[subject]...)
+}
@@ -0,0 +1,274 @@
+package goavro
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const registryContentType = "application/vnd.schemaregistry.v1+json"
+
+// RegistryClientConfig is used to specify creation parameters for RegistryClient.
+type RegistryClientConfig struct {
+	URL        string        // URL specifies the base URL of the schema registry, (required).
+	HTTPClient *http.Client  // HTTPClient specifies the client used to send requests, (optional). If omitted, a client using TLSConfig is created.
+	TLSConfig  *tls.Config   // TLSConfig specifies the TLS configuration of the created client, (optional). Ignored when HTTPClient is provided.
+	Username   string        // Username specifies the basic authentication user name, (optional).
+	Password   string        // Password specifies the basic authentication password, (optional).
+	Retries    int           // Retries specifies how many times failed requests are retried, (optional). If omitted, requests are not retried.
+	RetryDelay time.Duration // RetryDelay specifies the delay before the first retry, which doubles for each retry, (optional). If omitted, defaults to 100ms.
+}
+
+// RegistryError is the error returned when the schema registry responds to a request with an
+// error.  RegistryClient methods return it unwrapped, so callers may recover its codes with a type
+// assertion, and store the description of the failed operation in its Context field.
+type RegistryError struct {
+	StatusCode int    `json:"-"`          // HTTP status code of the response
+	ErrorCode  int    `json:"error_code"` // registry error code, such as 40401 when subject not found
+	Message    string `json:"message"`
+	Context    string `json:"-"` // operation that failed, such as "cannot fetch schema ID 7"
+}
+
+func (e RegistryError) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("%s: schema registry error %d: %s", e.Context, e.ErrorCode, e.Message)
+	}
+	return fmt.Sprintf("schema registry error %d: %s", e.ErrorCode, e.Message)
+}
+
+// registryClientError returns the error with the description of the failed operation.  It stores
+// the description in the Context field of a RegistryError, so the RegistryError is returned
+// unwrapped, and wraps other errors.
+func registryClientError(err error, format string, a ...interface{}) error {
+	context := fmt.Sprintf(format, a...)
+	if re, ok := err.(RegistryError); ok {
+		re.Context = context
+		return re
+	}
+	return fmt.Errorf("%s: %s", context, err)
+}
+
+// RegistrySchema describes one version of a schema registered under a subject.
+type RegistrySchema struct {
+	Subject string `json:"subject"`
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Schema  string `json:"schema"`
+}
+
+// RegistryClient is a SchemaRegistry that sends requests to a schema registry using the
+// Confluent Schema Registry REST API.  It stores the schema and Codec for each ID it has fetched,
+// because registered schemas never change.  It is safe for concurrent use.
+type RegistryClient struct {
+	baseURL    string
+	client     *http.Client
+	username   string
+	password   string
+	retries    int
+	retryDelay time.Duration
+
+	lock    sync.RWMutex
+	schemas map[int]string
+	codecs  map[int]*Codec
+}
+
+// NewRegistryClient returns a RegistryClient that sends requests to the schema registry at the
+// configured URL.
+func NewRegistryClient(config RegistryClientConfig) (*RegistryClient, error) {
+	if config.URL == "" {
+		return nil, errors.New("cannot create RegistryClient without URL")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("cannot create RegistryClient with invalid URL: %s", err)
+	}
+	if config.Retries < 0 {
+		return nil, fmt.Errorf("cannot create RegistryClient with negative Retries: %d", config.Retries)
+	}
+
+	rc := &RegistryClient{
+		baseURL:    strings.TrimRight(config.URL, "/"),
+		client:     config.HTTPClient,
+		username:   config.Username,
+		password:   config.Password,
+		retries:    config.Retries,
+		retryDelay: config.RetryDelay,
+		schemas:    make(map[int]string),
+		codecs:     make(map[int]*Codec),
+	}
+	if rc.client == nil {
+		rc.client = &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config.TLSConfig}}
+	}
+	if rc.retryDelay == 0 {
+		rc.retryDelay = 100 * time.Millisecond
+	}
+	return rc, nil
+}
+
+// Register stores the schema under the specified subject, and returns the ID of the schema.
+func (rc *RegistryClient) Register(subject, schema string) (int, error) {
+	var response struct {
+		ID int `json:"id"`
+	}
+	if err := rc.do("POST", "/subjects/"+url.PathEscape(subject)+"/versions", schemaRequest(schema), &response); err != nil {
+		return 0, registryClientError(err, "cannot register schema for subject %q", subject)
+	}
+
+	rc.lock.Lock()
+	rc.schemas[response.ID] = schema
+	rc.lock.Unlock()
+	return response.ID, nil
+}
+
+// SchemaByID returns the schema having the specified ID.
+func (rc *RegistryClient) SchemaByID(id int) (string, error) {
+	rc.lock.RLock()
+	schema, ok := rc.schemas[id]
+	rc.lock.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	var response struct {
+		Schema string `json:"schema"`
+	}
+	if err := rc.do("GET", "/schemas/ids/"+strconv.Itoa(id), nil, &response); err != nil {
+		return "", registryClientError(err, "cannot fetch schema ID %d", id)
+	}
+
+	rc.lock.Lock()
+	rc.schemas[id] = response.Schema
+	rc.lock.Unlock()
+	return response.Schema, nil
+}
+
+// CodecByID returns the Codec for the schema having the specified ID.
+func (rc *RegistryClient) CodecByID(id int) (*Codec, error) {
+	rc.lock.RLock()
+	c, ok := rc.codecs[id]
+	rc.lock.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	schema, err := rc.SchemaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c, err = NewCodec(schema); err != nil {
+		return nil, fmt.Errorf("cannot create codec for schema ID %d: %s", id, err)
+	}
+
+	rc.lock.Lock()
+	rc.codecs[id] = c
+	rc.lock.Unlock()
+	return c, nil
+}
+
+// LatestSchema returns the latest version of the schema registered under the specified subject.
+func (rc *RegistryClient) LatestSchema(subject string) (RegistrySchema, error) {
+	var response RegistrySchema
+	if err := rc.do("GET", "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &response); err != nil {
+		return RegistrySchema{}, registryClientError(err, "cannot fetch latest schema for subject %q", subject)
+	}
+	return response, nil
+}
+
+// IsCompatible returns true when the schema is compatible with the latest version of the schema
+// registered under the specified subject, according to the compatibility level of the subject.
+func (rc *RegistryClient) IsCompatible(subject, schema string) (bool, error) {
+	var response struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	if err := rc.do("POST", "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest", schemaRequest(schema), &response); err != nil {
+		return false, registryClientError(err, "cannot check compatibility for subject %q", subject)
+	}
+	return response.IsCompatible, nil
+}
+
+// Subjects returns the list of subjects registered with the schema registry.
+func (rc *RegistryClient) Subjects() ([]string, error) {
+	var response []string
+	if err := rc.do("GET", "/subjects", nil, &response); err != nil {
+		return nil, registryClientError(err, "cannot fetch subjects")
+	}
+	return response, nil
+}
+
+// schemaRequest returns the body of a request that sends a schema to the registry.
+func schemaRequest(schema string) []byte {
+	body, _ := json.Marshal(map[string]string{"schema": schema}) // marshaling a string map cannot fail
+	return body
+}
+
+// do sends a request to the registry, retrying when the request fails or the registry responds
+// with a server error, and decodes the JSON response into the value pointed to by response.
+func (rc *RegistryClient) do(method, path string, body []byte, response interface{}) error {
+	delay := rc.retryDelay
+	var err error
+	for attempt := 0; attempt <= rc.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		var retry bool
+		if retry, err = rc.send(method, path, body, response); err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// send sends one request to the registry.  It returns true along with the error when the request
+// may succeed if retried.
+func (rc *RegistryClient) send(method, path string, body []byte, response interface{}) (bool, error) {
+	var rbody io.Reader
+	if body != nil {
+		rbody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, rc.baseURL+path, rbody)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", registryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", registryContentType)
+	}
+	if rc.username != "" || rc.password != "" {
+		req.SetBasicAuth(rc.username, rc.password)
+	}
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	blob, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return true, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		re := RegistryError{StatusCode: resp.StatusCode}
+		if err = json.Unmarshal(blob, &re); err != nil || re.Message == "" {
+			re.Message = http.StatusText(resp.StatusCode)
+		}
+		if re.ErrorCode == 0 {
+			re.ErrorCode = resp.StatusCode
+		}
+		return resp.StatusCode >= 500, re
+	}
+	if err = json.Unmarshal(blob, response); err != nil {
+		return false, fmt.Errorf("cannot decode response: %s", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,232 @@
+package goavro_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+// newTestRegistryServer returns a server that responds to registry requests with canned
+// responses, after failing the first failures requests with a server error.
+func newTestRegistryServer(t *testing.T, failures int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error_code":40101,"message":"Unauthorized"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /subjects/t1-value/versions":
+			var request struct {
+				Schema string `json:"schema"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Schema != `"int"` {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				fmt.Fprint(w, `{"error_code":42201,"message":"Invalid schema"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id":7}`)
+		case "GET /schemas/ids/7":
+			fmt.Fprint(w, `{"schema":"\"int\""}`)
+		case "GET /subjects/t1-value/versions/latest":
+			fmt.Fprint(w, `{"subject":"t1-value","id":7,"version":2,"schema":"\"int\""}`)
+		case "POST /compatibility/subjects/t1-value/versions/latest":
+			fmt.Fprint(w, `{"is_compatible":true}`)
+		case "GET /subjects":
+			fmt.Fprint(w, `["t1-key","t1-value"]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error_code":40403,"message":"Schema not found"}`)
+		}
+	}))
+}
+
+func newTestRegistryClient(t *testing.T, url string, retries int) *goavro.RegistryClient {
+	rc, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{URL: url + "/", Username: "alice", Password: "secret", Retries: retries, RetryDelay: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rc
+}
+
+func TestRegistryClientConfig(t *testing.T) {
+	if _, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{}); err == nil || !strings.Contains(err.Error(), "without URL") {
+		t.Errorf("Actual: %v; Expected: %s", err, "without URL")
+	}
+	if _, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{URL: "http://localhost", Retries: -1}); err == nil || !strings.Contains(err.Error(), "negative Retries") {
+		t.Errorf("Actual: %v; Expected: %s", err, "negative Retries")
+	}
+}
+
+func TestRegistryClient(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 0, &requests)
+	defer server.Close()
+	rc := newTestRegistryClient(t, server.URL, 0)
+
+	id, err := rc.Register("t1-value", `"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := id, 7; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	latest, err := rc.LatestSchema("t1-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%+v", latest), `{Subject:t1-value ID:7 Version:2 Schema:"int"}`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	ok, err := rc.IsCompatible("t1-value", `"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Actual: %v; Expected: %v", ok, true)
+	}
+
+	subjects, err := rc.Subjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", subjects), "[t1-key t1-value]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryClientCachesByID(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 0, &requests)
+	defer server.Close()
+	rc := newTestRegistryClient(t, server.URL, 0)
+
+	for i := 0; i < 3; i++ {
+		c, err := rc.CodecByID(7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := c.CanonicalSchema(), `"int"`; actual != expected {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+	}
+	if actual, expected := atomic.LoadInt32(&requests), int32(1); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryClientRetries(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 2, &requests)
+	defer server.Close()
+
+	// not enough retries
+	_, err := newTestRegistryClient(t, server.URL, 1).SchemaByID(7)
+	re, ok := err.(goavro.RegistryError)
+	if !ok {
+		t.Fatalf("Actual: %#v; Expected: %s", err, "RegistryError")
+	}
+	if actual, expected := re.StatusCode, 503; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := re.Context, "cannot fetch schema ID 7"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if !strings.Contains(err.Error(), "cannot fetch schema ID 7: schema registry error 503") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot fetch schema ID 7: schema registry error 503")
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	schema, err := newTestRegistryClient(t, server.URL, 2).SchemaByID(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := schema, `"int"`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := atomic.LoadInt32(&requests), int32(3); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryClientDoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 0, &requests)
+	defer server.Close()
+
+	_, err := newTestRegistryClient(t, server.URL, 3).SchemaByID(8)
+	if err == nil || !strings.Contains(err.Error(), "schema registry error 40403: Schema not found") {
+		t.Errorf("Actual: %v; Expected: %s", err, "schema registry error 40403: Schema not found")
+	}
+	if re, ok := err.(goavro.RegistryError); !ok || re.StatusCode != 404 || re.ErrorCode != 40403 {
+		t.Errorf("Actual: %#v; Expected: %s", err, "RegistryError with codes 404 and 40403")
+	}
+	if actual, expected := atomic.LoadInt32(&requests), int32(1); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryClientBasicAuth(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 0, &requests)
+	defer server.Close()
+
+	rc, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rc.Subjects()
+	if err == nil || !strings.Contains(err.Error(), "schema registry error 40101") {
+		t.Errorf("Actual: %v; Expected: %s", err, "schema registry error 40101")
+	}
+}
+
+func TestRegistryClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["t1-value"]`)
+	}))
+	defer server.Close()
+
+	rc, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{URL: server.URL, TLSConfig: server.Client().Transport.(*http.Transport).TLSClientConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subjects, err := rc.Subjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", subjects), "[t1-value]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryClientWithConfluentDeserializer(t *testing.T) {
+	var requests int32
+	server := newTestRegistryServer(t, 0, &requests)
+	defer server.Close()
+
+	cd, err := goavro.NewConfluentDeserializer(newTestRegistryClient(t, server.URL, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _, err := cd.Deserialize([]byte{0, 0, 0, 0, 7, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, int32(3); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
@@ -0,0 +1,604 @@
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Compatibility levels enforced by RegistryServer when registering a new version of a subject.
+const (
+	CompatibilityNone               = "NONE"                // CompatibilityNone does not check compatibility.
+	CompatibilityBackward           = "BACKWARD"            // CompatibilityBackward requires the new schema can read data written with the latest version.
+	CompatibilityBackwardTransitive = "BACKWARD_TRANSITIVE" // CompatibilityBackwardTransitive requires the new schema can read data written with every version.
+	CompatibilityForward            = "FORWARD"             // CompatibilityForward requires the latest version can read data written with the new schema.
+	CompatibilityForwardTransitive  = "FORWARD_TRANSITIVE"  // CompatibilityForwardTransitive requires every version can read data written with the new schema.
+	CompatibilityFull               = "FULL"                // CompatibilityFull requires both backward and forward compatibility with the latest version.
+	CompatibilityFullTransitive     = "FULL_TRANSITIVE"     // CompatibilityFullTransitive requires both backward and forward compatibility with every version.
+)
+
+// Registry error codes, as used by the Confluent Schema Registry.
+const (
+	registryErrorSubjectNotFound      = 40401
+	registryErrorVersionNotFound      = 40402
+	registryErrorSchemaNotFound       = 40403
+	registryErrorIncompatibleSchema   = 409
+	registryErrorInvalidSchema        = 42201
+	registryErrorInvalidVersion       = 42202
+	registryErrorInvalidCompatibility = 42203
+	registryErrorInvalidSubject       = 42208
+	registryErrorStore                = 50001
+)
+
+const (
+	registryConfigFile                = "config.json"
+	registrySchemasDirectory          = "ids"
+	registrySubjectsDirectory         = "subjects"
+	registrySchemaFileExtension       = ".avsc"
+	registryDefaultCompatibilityLevel = CompatibilityBackward
+	registryMaximumRequestSize        = 16 << 20 // largest request body accepted, in bytes
+)
+
+// RegistryServer is an http.Handler that implements the core endpoints of the Confluent Schema
+// Registry REST API: subjects, versions, schemas by ID, compatibility checks, and compatibility
+// configuration.  It persists schemas as .avsc files in a directory, assigns the same ID to schemas
+// that differ only by documentation or by JSON formatting, and refuses to register a new version of
+// a subject that is not compatible with its existing versions according to the compatibility level
+// of the subject.
+//
+// The directory stores each schema as ids/ID.avsc, each version of a subject as
+// subjects/SUBJECT/VERSION.avsc, and the compatibility configuration as config.json.
+type RegistryServer struct {
+	dir string
+
+	lock          sync.RWMutex
+	schemas       []*registryServerSchema // schema of each ID, at index ID-1
+	idFromForm    map[string]int          // ID of each registry form
+	subjects      map[string][]int        // IDs of each version of a subject, in version order
+	compatibility registryServerConfig
+}
+
+// registryServerSchema stores a registered schema along with its codec.
+type registryServerSchema struct {
+	schema string
+	codec  *Codec
+}
+
+// registryServerConfig stores the compatibility configuration of a RegistryServer.
+type registryServerConfig struct {
+	CompatibilityLevel string            `json:"compatibilityLevel"`
+	Subjects           map[string]string `json:"subjects,omitempty"` // compatibility level of subjects that override the global level
+}
+
+// NewRegistryServer returns a RegistryServer that persists schemas in the specified directory,
+// loading any schemas already stored there.
+func NewRegistryServer(dir string) (*RegistryServer, error) {
+	rs := &RegistryServer{
+		dir:           dir,
+		idFromForm:    make(map[string]int),
+		subjects:      make(map[string][]int),
+		compatibility: registryServerConfig{CompatibilityLevel: registryDefaultCompatibilityLevel, Subjects: make(map[string]string)},
+	}
+	for _, d := range []string{dir, filepath.Join(dir, registrySchemasDirectory), filepath.Join(dir, registrySubjectsDirectory)} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, fmt.Errorf("cannot create registry directory: %s", err)
+		}
+	}
+	if err := rs.load(); err != nil {
+		return nil, fmt.Errorf("cannot load registry from %q: %s", dir, err)
+	}
+	return rs, nil
+}
+
+// load reads the schemas, subjects, and compatibility configuration stored in the directory.
+func (rs *RegistryServer) load() error {
+	blob, err := ioutil.ReadFile(filepath.Join(rs.dir, registryConfigFile))
+	if err == nil {
+		if err = json.Unmarshal(blob, &rs.compatibility); err != nil {
+			return fmt.Errorf("cannot decode %s: %s", registryConfigFile, err)
+		}
+		if rs.compatibility.Subjects == nil {
+			rs.compatibility.Subjects = make(map[string]string)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// NOTE: Read schemas in order of their IDs, so IDs remain dense.
+	ids, err := registryFileNumbers(filepath.Join(rs.dir, registrySchemasDirectory))
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if id != i+1 {
+			return fmt.Errorf("cannot find schema ID %d", i+1)
+		}
+		blob, err := ioutil.ReadFile(filepath.Join(rs.dir, registrySchemasDirectory, strconv.Itoa(id)+registrySchemaFileExtension))
+		if err != nil {
+			return err
+		}
+		c, err := NewCodec(string(blob))
+		if err != nil {
+			return fmt.Errorf("cannot create codec for schema ID %d: %s", id, err)
+		}
+		form, err := registryForm(c.Schema())
+		if err != nil {
+			return fmt.Errorf("cannot create codec for schema ID %d: %s", id, err)
+		}
+		rs.schemas = append(rs.schemas, &registryServerSchema{schema: string(blob), codec: c})
+		rs.idFromForm[form] = id
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(rs.dir, registrySubjectsDirectory))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subject, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			return fmt.Errorf("cannot decode subject directory name %q: %s", entry.Name(), err)
+		}
+		subjectDir := filepath.Join(rs.dir, registrySubjectsDirectory, entry.Name())
+		versions, err := registryFileNumbers(subjectDir)
+		if err != nil {
+			return err
+		}
+		for i, version := range versions {
+			if version != i+1 {
+				return fmt.Errorf("cannot find subject %q version %d", subject, i+1)
+			}
+			blob, err := ioutil.ReadFile(filepath.Join(subjectDir, strconv.Itoa(version)+registrySchemaFileExtension))
+			if err != nil {
+				return err
+			}
+			c, err := NewCodec(string(blob))
+			if err != nil {
+				return fmt.Errorf("cannot create codec for subject %q version %d: %s", subject, version, err)
+			}
+			form, err := registryForm(c.Schema())
+			if err != nil {
+				return fmt.Errorf("cannot create codec for subject %q version %d: %s", subject, version, err)
+			}
+			id, ok := rs.idFromForm[form]
+			if !ok {
+				return fmt.Errorf("cannot find schema ID for subject %q version %d", subject, version)
+			}
+			rs.subjects[subject] = append(rs.subjects[subject], id)
+		}
+	}
+	return nil
+}
+
+// registryFileNumbers returns the sorted numbers of the .avsc files in the directory, whose names
+// are positive integers.
+func registryFileNumbers(dir string) ([]int, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, registrySchemaFileExtension) {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimSuffix(name, registrySchemaFileExtension))
+		if err != nil || number < 1 {
+			return nil, fmt.Errorf("cannot parse number from file name: %q", filepath.Join(dir, name))
+		}
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// writeRegistryFile writes the file by writing a temporary file and renaming it, so a partially
+// written file is never read.
+func writeRegistryFile(pathname string, blob []byte) error {
+	if err := os.MkdirAll(filepath.Dir(pathname), 0755); err != nil {
+		return err
+	}
+	tempname := pathname + ".tmp"
+	if err := ioutil.WriteFile(tempname, blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempname, pathname)
+}
+
+// registryHTTPError is returned by request handlers to respond with an error.
+type registryHTTPError struct {
+	statusCode int
+	errorCode  int
+	message    string
+}
+
+func (e *registryHTTPError) Error() string {
+	return e.message
+}
+
+func newRegistryHTTPError(statusCode, errorCode int, format string, a ...interface{}) *registryHTTPError {
+	return &registryHTTPError{statusCode: statusCode, errorCode: errorCode, message: fmt.Sprintf(format, a...)}
+}
+
+// ServeHTTP responds to schema registry requests.
+func (rs *RegistryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	response, err := rs.route(r)
+	w.Header().Set("Content-Type", registryContentType)
+	if err != nil {
+		he, ok := err.(*registryHTTPError)
+		if !ok {
+			he = newRegistryHTTPError(http.StatusInternalServerError, registryErrorStore, "%s", err)
+		}
+		w.WriteHeader(he.statusCode)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"error_code": he.errorCode, "message": he.message})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// route invokes the handler for the request, and returns the value to send as the JSON response.
+func (rs *RegistryServer) route(r *http.Request) (interface{}, error) {
+	path := r.URL.EscapedPath()
+	var components []string
+	for _, component := range strings.Split(strings.Trim(path, "/"), "/") {
+		unescaped, err := url.PathUnescape(component)
+		if err != nil {
+			return nil, newRegistryHTTPError(http.StatusBadRequest, http.StatusBadRequest, "cannot decode path: %s", err)
+		}
+		components = append(components, unescaped)
+	}
+
+	switch {
+	case r.Method == "GET" && len(components) == 1 && components[0] == "subjects":
+		return rs.getSubjects(), nil
+	case r.Method == "POST" && len(components) == 2 && components[0] == "subjects":
+		return rs.postSubject(components[1], r)
+	case r.Method == "GET" && len(components) == 3 && components[0] == "subjects" && components[2] == "versions":
+		return rs.getVersions(components[1])
+	case r.Method == "POST" && len(components) == 3 && components[0] == "subjects" && components[2] == "versions":
+		return rs.postVersion(components[1], r)
+	case r.Method == "GET" && len(components) == 4 && components[0] == "subjects" && components[2] == "versions":
+		return rs.getVersion(components[1], components[3])
+	case r.Method == "GET" && len(components) == 3 && components[0] == "schemas" && components[1] == "ids":
+		return rs.getSchema(components[2])
+	case r.Method == "POST" && len(components) == 5 && components[0] == "compatibility" && components[1] == "subjects" && components[3] == "versions":
+		return rs.postCompatibility(components[2], components[4], r)
+	case r.Method == "GET" && len(components) == 1 && components[0] == "config":
+		return rs.getConfig(""), nil
+	case r.Method == "PUT" && len(components) == 1 && components[0] == "config":
+		return rs.putConfig("", r)
+	case r.Method == "GET" && len(components) == 2 && components[0] == "config":
+		if err := checkSubject(components[1]); err != nil {
+			return nil, err
+		}
+		return rs.getConfig(components[1]), nil
+	case r.Method == "PUT" && len(components) == 2 && components[0] == "config":
+		if err := checkSubject(components[1]); err != nil {
+			return nil, err
+		}
+		return rs.putConfig(components[1], r)
+	}
+	return nil, newRegistryHTTPError(http.StatusNotFound, http.StatusNotFound, "cannot find resource: %s %s", r.Method, path)
+}
+
+// decodeRequest decodes the JSON request body into the value pointed to by request.
+func decodeRequest(r *http.Request, request interface{}) error {
+	blob, err := ioutil.ReadAll(io.LimitReader(r.Body, registryMaximumRequestSize+1))
+	if err != nil {
+		return newRegistryHTTPError(http.StatusBadRequest, http.StatusBadRequest, "cannot read request body: %s", err)
+	}
+	if len(blob) > registryMaximumRequestSize {
+		return newRegistryHTTPError(http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, "cannot read request body larger than %d bytes", registryMaximumRequestSize)
+	}
+	if err = json.Unmarshal(blob, request); err != nil {
+		return newRegistryHTTPError(http.StatusBadRequest, http.StatusBadRequest, "cannot decode request body: %s", err)
+	}
+	return nil
+}
+
+// decodeSchemaRequest decodes the schema from the JSON request body, and returns it along with
+// its codec.
+func decodeSchemaRequest(r *http.Request) (string, *Codec, error) {
+	var request struct {
+		Schema string `json:"schema"`
+	}
+	if err := decodeRequest(r, &request); err != nil {
+		return "", nil, err
+	}
+	c, err := NewCodec(request.Schema)
+	if err != nil {
+		return "", nil, newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidSchema, "Invalid schema: %s", err)
+	}
+	return request.Schema, c, nil
+}
+
+// checkSubject returns an error when the subject is empty, or names a relative directory, which
+// cannot be stored as the directory of the subject.
+func checkSubject(subject string) error {
+	switch subject {
+	case "", ".", "..":
+		return newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidSubject, "Invalid subject: %q", subject)
+	}
+	return nil
+}
+
+func (rs *RegistryServer) getSubjects() []string {
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	subjects := make([]string, 0, len(rs.subjects))
+	for subject := range rs.subjects {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+func (rs *RegistryServer) getVersions(subject string) ([]int, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	ids, ok := rs.subjects[subject]
+	if !ok {
+		return nil, newRegistryHTTPError(http.StatusNotFound, registryErrorSubjectNotFound, "Subject %q not found", subject)
+	}
+	versions := make([]int, len(ids))
+	for i := range ids {
+		versions[i] = i + 1
+	}
+	return versions, nil
+}
+
+// version returns the version number for the version string, which is either a positive integer
+// or "latest".  The caller must hold the lock.
+func (rs *RegistryServer) version(subject, versionString string) (int, error) {
+	ids, ok := rs.subjects[subject]
+	if !ok {
+		return 0, newRegistryHTTPError(http.StatusNotFound, registryErrorSubjectNotFound, "Subject %q not found", subject)
+	}
+	if versionString == "latest" {
+		return len(ids), nil
+	}
+	version, err := strconv.Atoi(versionString)
+	if err != nil || version < 1 {
+		return 0, newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidVersion, "Invalid version: %q", versionString)
+	}
+	if version > len(ids) {
+		return 0, newRegistryHTTPError(http.StatusNotFound, registryErrorVersionNotFound, "Subject %q version %d not found", subject, version)
+	}
+	return version, nil
+}
+
+func (rs *RegistryServer) getVersion(subject, versionString string) (*RegistrySchema, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	version, err := rs.version(subject, versionString)
+	if err != nil {
+		return nil, err
+	}
+	id := rs.subjects[subject][version-1]
+	return &RegistrySchema{Subject: subject, ID: id, Version: version, Schema: rs.schemas[id-1].schema}, nil
+}
+
+func (rs *RegistryServer) getSchema(idString string) (interface{}, error) {
+	id, err := strconv.Atoi(idString)
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	if err != nil || id < 1 || id > len(rs.schemas) {
+		return nil, newRegistryHTTPError(http.StatusNotFound, registryErrorSchemaNotFound, "Schema %q not found", idString)
+	}
+	return map[string]string{"schema": rs.schemas[id-1].schema}, nil
+}
+
+// postSubject returns the version of the subject having the same registry form as the schema in
+// the request.
+func (rs *RegistryServer) postSubject(subject string, r *http.Request) (*RegistrySchema, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	_, c, err := decodeSchemaRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	form, err := registryForm(c.Schema())
+	if err != nil {
+		return nil, newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidSchema, "Invalid schema: %s", err)
+	}
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	ids, ok := rs.subjects[subject]
+	if !ok {
+		return nil, newRegistryHTTPError(http.StatusNotFound, registryErrorSubjectNotFound, "Subject %q not found", subject)
+	}
+	if id, ok := rs.idFromForm[form]; ok {
+		for i, vid := range ids {
+			if vid == id {
+				return &RegistrySchema{Subject: subject, ID: id, Version: i + 1, Schema: rs.schemas[id-1].schema}, nil
+			}
+		}
+	}
+	return nil, newRegistryHTTPError(http.StatusNotFound, registryErrorSchemaNotFound, "Schema not found")
+}
+
+// postVersion registers the schema in the request as a new version of the subject, unless the
+// subject already has a version with the same registry form.
+func (rs *RegistryServer) postVersion(subject string, r *http.Request) (interface{}, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	schema, c, err := decodeSchemaRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	form, err := registryForm(c.Schema())
+	if err != nil {
+		return nil, newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidSchema, "Invalid schema: %s", err)
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	id, known := rs.idFromForm[form]
+	ids := rs.subjects[subject]
+	if known {
+		for _, vid := range ids {
+			if vid == id {
+				return map[string]int{"id": id}, nil
+			}
+		}
+	}
+
+	if err = rs.checkCompatibility(subject, c, ids); err != nil {
+		return nil, err
+	}
+
+	if !known {
+		id = len(rs.schemas) + 1
+		if err = writeRegistryFile(filepath.Join(rs.dir, registrySchemasDirectory, strconv.Itoa(id)+registrySchemaFileExtension), []byte(schema)); err != nil {
+			return nil, err
+		}
+		rs.schemas = append(rs.schemas, &registryServerSchema{schema: schema, codec: c})
+		rs.idFromForm[form] = id
+	}
+	version := len(ids) + 1
+	if err = writeRegistryFile(filepath.Join(rs.dir, registrySubjectsDirectory, url.PathEscape(subject), strconv.Itoa(version)+registrySchemaFileExtension), []byte(schema)); err != nil {
+		return nil, err
+	}
+	rs.subjects[subject] = append(ids, id)
+	return map[string]int{"id": id}, nil
+}
+
+// checkCompatibility returns an error unless the codec is compatible with the versions of the
+// subject having the specified IDs, according to the compatibility level of the subject.  The
+// caller must hold the lock.
+func (rs *RegistryServer) checkCompatibility(subject string, c *Codec, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	level := rs.compatibilityLevel(subject)
+
+	var backward, forward bool
+	switch level {
+	case CompatibilityNone:
+		return nil
+	case CompatibilityBackward, CompatibilityBackwardTransitive:
+		backward = true
+	case CompatibilityForward, CompatibilityForwardTransitive:
+		forward = true
+	case CompatibilityFull, CompatibilityFullTransitive:
+		backward, forward = true, true
+	}
+	if !strings.HasSuffix(level, "_TRANSITIVE") {
+		ids = ids[len(ids)-1:]
+	}
+
+	for _, id := range ids {
+		existing := rs.schemas[id-1].codec
+		if backward {
+			if err := checkCompatible(existing, c); err != nil {
+				return newRegistryHTTPError(http.StatusConflict, registryErrorIncompatibleSchema, "Schema being registered is incompatible with an earlier schema for subject %q: %s: %s", subject, level, err)
+			}
+		}
+		if forward {
+			if err := checkCompatible(c, existing); err != nil {
+				return newRegistryHTTPError(http.StatusConflict, registryErrorIncompatibleSchema, "Schema being registered is incompatible with an earlier schema for subject %q: %s: %s", subject, level, err)
+			}
+		}
+	}
+	return nil
+}
+
+// postCompatibility returns whether the schema in the request is compatible with the specified
+// version of the subject, according to the compatibility level of the subject.
+func (rs *RegistryServer) postCompatibility(subject, versionString string, r *http.Request) (interface{}, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	_, c, err := decodeSchemaRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	version, err := rs.version(subject, versionString)
+	if err != nil {
+		return nil, err
+	}
+	ids := rs.subjects[subject][:version]
+	if versionString != "latest" {
+		ids = ids[version-1:]
+	}
+	err = rs.checkCompatibility(subject, c, ids)
+	if _, ok := err.(*registryHTTPError); err != nil && !ok {
+		return nil, err
+	}
+	return map[string]bool{"is_compatible": err == nil}, nil
+}
+
+// compatibilityLevel returns the compatibility level of the subject, or the global level when
+// subject is empty or has no level of its own.  The caller must hold the lock.
+func (rs *RegistryServer) compatibilityLevel(subject string) string {
+	if level, ok := rs.compatibility.Subjects[subject]; ok {
+		return level
+	}
+	return rs.compatibility.CompatibilityLevel
+}
+
+func (rs *RegistryServer) getConfig(subject string) interface{} {
+	rs.lock.RLock()
+	defer rs.lock.RUnlock()
+	return map[string]string{"compatibilityLevel": rs.compatibilityLevel(subject)}
+}
+
+func (rs *RegistryServer) putConfig(subject string, r *http.Request) (interface{}, error) {
+	var request struct {
+		Compatibility string `json:"compatibility"`
+	}
+	if err := decodeRequest(r, &request); err != nil {
+		return nil, err
+	}
+	level := strings.ToUpper(request.Compatibility)
+	switch level {
+	case CompatibilityNone, CompatibilityBackward, CompatibilityBackwardTransitive, CompatibilityForward, CompatibilityForwardTransitive, CompatibilityFull, CompatibilityFullTransitive:
+	default:
+		return nil, newRegistryHTTPError(http.StatusUnprocessableEntity, registryErrorInvalidCompatibility, "Invalid compatibility level: %q", request.Compatibility)
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	config := registryServerConfig{CompatibilityLevel: rs.compatibility.CompatibilityLevel, Subjects: make(map[string]string, len(rs.compatibility.Subjects)+1)}
+	for k, v := range rs.compatibility.Subjects {
+		config.Subjects[k] = v
+	}
+	if subject == "" {
+		config.CompatibilityLevel = level
+	} else {
+		config.Subjects[subject] = level
+	}
+	blob, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if err = writeRegistryFile(filepath.Join(rs.dir, registryConfigFile), blob); err != nil {
+		return nil, err
+	}
+	rs.compatibility = config
+	return map[string]string{"compatibility": level}, nil
+}
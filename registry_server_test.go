@@ -0,0 +1,298 @@
+package goavro_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+const (
+	registryServerSchemaV1 = `{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`
+	registryServerSchemaV2 = `{"type":"record","name":"r1","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bravo"}]}`
+	registryServerSchemaV3 = `{"type":"record","name":"r1","fields":[{"name":"b","type":"string"}]}`
+)
+
+// newTestRegistryServerDirectory returns a client for a RegistryServer persisting to the
+// specified directory, along with a function that stops the server.
+func newTestRegistryServerDirectory(t *testing.T, dir string) (*goavro.RegistryClient, func()) {
+	rs, err := goavro.NewRegistryServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(rs)
+	rc, err := goavro.NewRegistryClient(goavro.RegistryClientConfig{URL: server.URL})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return rc, server.Close
+}
+
+func newTestTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "goavro-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRegistryServerRegister(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+	rc, stop := newTestRegistryServerDirectory(t, dir)
+	defer stop()
+
+	id1, err := rc.Register("t1-value", registryServerSchemaV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// same canonical form yields same ID without a new version
+	id, err := rc.Register("t1-value", `{"name":"r1","doc":"same","type":"record","fields":[{"name":"a","type":{"type":"int"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != id1 {
+		t.Errorf("Actual: %v; Expected: %v", id, id1)
+	}
+	// same schema under another subject yields same ID
+	if id, err = rc.Register("t2-value", registryServerSchemaV1); err != nil {
+		t.Fatal(err)
+	}
+	if id != id1 {
+		t.Errorf("Actual: %v; Expected: %v", id, id1)
+	}
+	id2, err := rc.Register("t1-value", registryServerSchemaV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id2 == id1 {
+		t.Errorf("Actual: %v; Expected: not %v", id2, id1)
+	}
+
+	latest, err := rc.LatestSchema("t1-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%d %d %s", latest.ID, latest.Version, latest.Schema), fmt.Sprintf("%d 2 %s", id2, registryServerSchemaV2); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	subjects, err := rc.Subjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", subjects), "[t1-value t2-value]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	c, err := rc.CodecByID(id1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := c.CanonicalSchema(), `{"name":"r1","type":"record","fields":[{"name":"a","type":"int"}]}`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryServerPersists(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+
+	rc, stop := newTestRegistryServerDirectory(t, dir)
+	if _, err := rc.Register("t1/value", registryServerSchemaV1); err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	if _, err := rc.Register("t1/value", registryServerSchemaV2); err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	stop()
+
+	for _, name := range []string{"ids/1.avsc", "ids/2.avsc", "subjects/t1%2Fvalue/1.avsc", "subjects/t1%2Fvalue/2.avsc"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	rc, stop = newTestRegistryServerDirectory(t, dir)
+	defer stop()
+	latest, err := rc.LatestSchema("t1/value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%d %d", latest.ID, latest.Version), "2 2"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	id, err := rc.Register("t1-key", `"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := id, 3; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestRegistryServerCompatibility(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+	rc, stop := newTestRegistryServerDirectory(t, dir)
+	defer stop()
+
+	if _, err := rc.Register("t1-value", registryServerSchemaV1); err != nil {
+		t.Fatal(err)
+	}
+
+	// BACKWARD by default: V3 cannot read field b from V1 data
+	ok, err := rc.IsCompatible("t1-value", registryServerSchemaV3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("Actual: %v; Expected: %v", ok, false)
+	}
+	_, err = rc.Register("t1-value", registryServerSchemaV3)
+	if err == nil || !strings.Contains(err.Error(), "schema registry error 409") {
+		t.Errorf("Actual: %v; Expected: %s", err, "schema registry error 409")
+	}
+
+	ok, err = rc.IsCompatible("t1-value", registryServerSchemaV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Actual: %v; Expected: %v", ok, true)
+	}
+	if _, err = rc.Register("t1-value", registryServerSchemaV2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryServerConfig(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+	rs, err := goavro.NewRegistryServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(rs)
+	defer server.Close()
+
+	testRegistryRequest(t, server.URL, "GET", "/config", "", 200, `{"compatibilityLevel":"BACKWARD"}`)
+	testRegistryRequest(t, server.URL, "PUT", "/config", `{"compatibility":"bogus"}`, 422, `"error_code":42203`)
+	testRegistryRequest(t, server.URL, "PUT", "/config/t1-value", `{"compatibility":"FORWARD"}`, 200, `{"compatibility":"FORWARD"}`)
+	testRegistryRequest(t, server.URL, "GET", "/config/t1-value", "", 200, `{"compatibilityLevel":"FORWARD"}`)
+	testRegistryRequest(t, server.URL, "GET", "/config/t2-value", "", 200, `{"compatibilityLevel":"BACKWARD"}`)
+
+	// FORWARD: existing schema must read data written with new schema, so adding a field with a
+	// default value is permitted, while removing a field without a default value is not
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(registryServerSchemaV2), 200, `{"id":1}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(`{"type":"record","name":"r1","fields":[{"name":"a","type":"long"}]}`), 200, `{"id":2}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(`{"type":"record","name":"r1","fields":[{"name":"c","type":"long"}]}`), 409, `"error_code":409`)
+
+	// NONE permits any schema
+	testRegistryRequest(t, server.URL, "PUT", "/config/t1-value", `{"compatibility":"NONE"}`, 200, `{"compatibility":"NONE"}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(`"string"`), 200, `{"id":3}`)
+
+	// configuration persists
+	rs, err = goavro.NewRegistryServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server2 := httptest.NewServer(rs)
+	defer server2.Close()
+	testRegistryRequest(t, server2.URL, "GET", "/config/t1-value", "", 200, `{"compatibilityLevel":"NONE"}`)
+	testRegistryRequest(t, server2.URL, "GET", "/subjects/t1-value/versions", "", 200, `[1,2,3]`)
+}
+
+func TestRegistryServerEnumCompatibility(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+	rs, err := goavro.NewRegistryServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(rs)
+	defer server.Close()
+
+	// BACKWARD: new schema without a default cannot read removed symbol
+	testRegistryRequest(t, server.URL, "POST", "/subjects/e1/versions", schemaRequestBody(`{"type":"enum","name":"e1","symbols":["A","B"]}`), 200, `{"id":1}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/e1/versions", schemaRequestBody(`{"type":"enum","name":"e1","symbols":["A"]}`), 409, `"error_code":409`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/e1/versions", schemaRequestBody(`{"type":"enum","name":"e1","symbols":["A","C"],"default":"C"}`), 200, `{"id":2}`)
+}
+
+func TestRegistryServerErrors(t *testing.T) {
+	dir := newTestTempDir(t)
+	defer os.RemoveAll(dir)
+	rs, err := goavro.NewRegistryServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(rs)
+	defer server.Close()
+
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(`"integer"`), 422, `"error_code":42201`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", `{`, 400, `"error_code":400`)
+	testRegistryRequest(t, server.URL, "GET", "/subjects/t1-value/versions", "", 404, `"error_code":40401`)
+	testRegistryRequest(t, server.URL, "GET", "/schemas/ids/1", "", 404, `"error_code":40403`)
+	testRegistryRequest(t, server.URL, "GET", "/bogus", "", 404, `"error_code":404`)
+
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value/versions", schemaRequestBody(`"int"`), 200, `{"id":1}`)
+	testRegistryRequest(t, server.URL, "GET", "/subjects/t1-value/versions/2", "", 404, `"error_code":40402`)
+	testRegistryRequest(t, server.URL, "GET", "/subjects/t1-value/versions/zero", "", 422, `"error_code":42202`)
+	testRegistryRequest(t, server.URL, "GET", "/subjects/t1-value/versions/1", "", 200, `{"subject":"t1-value","id":1,"version":1,"schema":"\"int\""}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value", schemaRequestBody(`{"type":"int"}`), 200, `{"subject":"t1-value","id":1,"version":1,"schema":"\"int\""}`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value", schemaRequestBody(`"long"`), 404, `"error_code":40403`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t1-value", schemaRequestBody(`{"type":"int","logicalType":"date"}`), 404, `"error_code":40403`)
+	testRegistryRequest(t, server.URL, "POST", "/subjects/t2-value/versions", schemaRequestBody(`{"type":"int","logicalType":"date"}`), 200, `{"id":2}`)
+
+	// NOTE: Subjects are stored as directories, so neither empty nor relative directory names are
+	// valid subjects.
+	for _, subject := range []string{"", ".", "..", "%2E%2E"} {
+		testRegistryRequest(t, server.URL, "POST", "/subjects/"+subject+"/versions", schemaRequestBody(`"int"`), 422, `"error_code":42208`)
+		testRegistryRequest(t, server.URL, "GET", "/subjects/"+subject+"/versions", "", 422, `"error_code":42208`)
+		testRegistryRequest(t, server.URL, "GET", "/subjects/"+subject+"/versions/1", "", 422, `"error_code":42208`)
+		testRegistryRequest(t, server.URL, "POST", "/compatibility/subjects/"+subject+"/versions/1", schemaRequestBody(`"int"`), 422, `"error_code":42208`)
+	}
+	testRegistryRequest(t, server.URL, "GET", "/subjects", "", 200, `["t1-value","t2-value"]`)
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "subjects"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := len(entries), 2; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func schemaRequestBody(schema string) string {
+	return fmt.Sprintf(`{"schema":%q}`, schema)
+}
+
+func testRegistryRequest(t *testing.T, baseURL, method, path, body string, statusCode int, expected string) {
+	t.Helper()
+	req, err := http.NewRequest(method, baseURL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	blob, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != statusCode {
+		t.Errorf("%s %s: Actual: %v; Expected: %v", method, path, resp.StatusCode, statusCode)
+	}
+	if !bytes.Contains(blob, []byte(expected)) {
+		t.Errorf("%s %s: Actual: %s; Expected: %s", method, path, blob, expected)
+	}
+}
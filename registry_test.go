@@ -0,0 +1,95 @@
+package goavro_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestMemorySchemaRegistry(t *testing.T) {
+	msr := goavro.NewMemorySchemaRegistry()
+
+	id1, err := msr.Register("s1", `{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// same canonical form yields same ID, and does not add version
+	id2, err := msr.Register("s1", `{"name":"r1","doc":"same","type":"record","fields":[{"name":"a","type":{"type":"int"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id1 != id2 {
+		t.Errorf("Actual: %v; Expected: %v", id2, id1)
+	}
+	id3, err := msr.Register("s1", `"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id3 == id1 {
+		t.Errorf("Actual: %v; Expected: not %v", id3, id1)
+	}
+	if _, err = msr.Register("s2", `"long"`); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := fmt.Sprintf("%v", msr.Subjects()), "[s1 s2]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", msr.Versions("s1")), fmt.Sprintf("[%d %d]", id1, id3); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	schema, err := msr.SchemaByID(id3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := schema, `"long"`; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestMemorySchemaRegistryLogicalTypes(t *testing.T) {
+	msr := goavro.NewMemorySchemaRegistry()
+
+	// NOTE: Parsing Canonical Form strips logical types, defaults, and aliases, so these schemas
+	// share a canonical form, but ought to be assigned distinct IDs.
+	schemas := []string{
+		`"long"`,
+		`{"type":"long","logicalType":"timestamp-millis"}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int","default":1}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int","aliases":["b"]}]}`,
+	}
+	ids := make(map[int]string)
+	for i, schema := range schemas {
+		id, err := msr.Register(fmt.Sprintf("s%d", i), schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, ok := ids[id]; ok {
+			t.Errorf("schemas %s and %s; Actual: %v; Expected: distinct IDs", other, schema, id)
+		}
+		ids[id] = schema
+		actual, err := msr.SchemaByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != schema {
+			t.Errorf("Actual: %v; Expected: %v", actual, schema)
+		}
+	}
+}
+
+func TestMemorySchemaRegistryFail(t *testing.T) {
+	msr := goavro.NewMemorySchemaRegistry()
+	if _, err := msr.Register("", `"int"`); err == nil || !strings.Contains(err.Error(), "without subject") {
+		t.Errorf("Actual: %v; Expected: %s", err, "without subject")
+	}
+	if _, err := msr.Register("s1", `"integer"`); err == nil || !strings.Contains(err.Error(), "cannot register invalid schema") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot register invalid schema")
+	}
+	if _, err := msr.SchemaByID(1); err == nil || !strings.Contains(err.Error(), "cannot find schema with ID: 1") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot find schema with ID: 1")
+	}
+}
@@ -0,0 +1,458 @@
+package goavro
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NewResolvingCodec returns a Codec that decodes data encoded with the writer schema into data
+// shaped by the reader schema, following the schema resolution rules of the Avro specification.
+// Fields are matched by name regardless of their order, writer fields absent from the reader schema
+// are skipped, and reader fields absent from the writer schema are filled with their default
+// values.  Numeric values are promoted from int to long, float, or double, from long to float or
+// double, and from float to double, and values are converted between string and bytes.  Enum
+// symbols are mapped by name, and union members are matched by type.
+//
+// Only BinaryDecode and Unmarshal resolve the writer schema to the reader schema.  BinaryEncode,
+// Marshal, TextDecode, and TextEncode operate using the reader schema, so data may be re-encoded
+// using the reader schema.
+func NewResolvingCodec(writerSchema, readerSchema string) (*Codec, error) {
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create codec for writer schema: %s", err)
+	}
+	reader, err := NewCodec(readerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create codec for reader schema: %s", err)
+	}
+	return newResolvingCodec(writer, reader)
+}
+
+func newResolvingCodec(writer, reader *Codec) (*Codec, error) {
+	rs := &resolver{records: make(map[[2]*Codec]*func([]byte) (interface{}, []byte, error))}
+	decoder, err := rs.resolve(writer, reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve writer schema to reader schema: %s", err)
+	}
+	c := *reader
+	c.binaryDecoder = decoder
+	c.binarySkipper = writer.binarySkipper // NOTE: data is encoded using the writer schema
+	c.reader = reader
+	c.writer = writer
+	c.plans = new(sync.Map) // NOTE: plans decode data written with the writer schema
+	return &c, nil
+}
+
+// checkCompatible returns an error unless data written with the writer schema can always be read
+// with the reader schema.  Unlike NewResolvingCodec, it also rejects writer enum symbols the reader
+// cannot represent and writer union members the reader cannot resolve, which would otherwise only
+// cause an error when such a value is decoded.
+func checkCompatible(writer, reader *Codec) error {
+	rs := &resolver{strict: true, records: make(map[[2]*Codec]*func([]byte) (interface{}, []byte, error))}
+	_, err := rs.resolve(writer, reader)
+	return err
+}
+
+// resolver builds decoders that read data encoded with a writer schema into data shaped by a
+// reader schema.
+type resolver struct {
+	// strict is true when every writer value must be able to be resolved, rather than only
+	// reporting an error when decoding a value that cannot be resolved.
+	strict bool
+
+	// records stores the decoder for each pair of writer and reader records while it is being
+	// built, so recursive records may refer to the decoder before it is complete.
+	records map[[2]*Codec]*func([]byte) (interface{}, []byte, error)
+}
+
+// resolve returns a decoder that reads a value encoded by the writer codec, and returns the value
+// as it would be decoded by the reader codec.
+func (rs *resolver) resolve(w, r *Codec) (func([]byte) (interface{}, []byte, error), error) {
+	// NOTE: Logical types do not affect how values are encoded, so resolve the underlying types,
+	// and convert the resolved value to the reader's logical type.
+	if w.underlying != nil {
+		w = w.underlying
+	}
+	if r.underlying != nil {
+		decoder, err := rs.resolve(w, r.underlying)
+		if err != nil {
+			return nil, err
+		}
+		toNative := r.toNative
+		return func(buf []byte) (interface{}, []byte, error) {
+			value, buf, err := decoder(buf)
+			if err != nil {
+				return nil, buf, err
+			}
+			if value, err = toNative(value); err != nil {
+				return nil, buf, err
+			}
+			return value, buf, nil
+		}, nil
+	}
+
+	if w.kind == "union" {
+		return rs.resolveWriterUnion(w, r)
+	}
+	if r.kind == "union" {
+		return rs.resolveReaderUnion(w, r)
+	}
+
+	switch r.kind {
+	case "array":
+		if w.kind != "array" {
+			break
+		}
+		itemDecoder, err := rs.resolve(w.items, r.items)
+		if err != nil {
+			return nil, fmt.Errorf("Array items: %s", err)
+		}
+		return func(buf []byte) (interface{}, []byte, error) {
+			return genericArrayBinaryDecoder(buf, itemDecoder)
+		}, nil
+	case "map":
+		if w.kind != "map" {
+			break
+		}
+		valueDecoder, err := rs.resolve(w.items, r.items)
+		if err != nil {
+			return nil, fmt.Errorf("Map values: %s", err)
+		}
+		return func(buf []byte) (interface{}, []byte, error) {
+			return genericMapBinaryDecoder(buf, valueDecoder)
+		}, nil
+	case "enum":
+		if w.kind != "enum" || !namesMatch(w, r) {
+			break
+		}
+		if rs.strict && r.symbolDefault == "" {
+			readerSymbols := make(map[string]struct{}, len(r.symbols))
+			for _, symbol := range r.symbols {
+				readerSymbols[symbol] = struct{}{}
+			}
+			for _, symbol := range w.symbols {
+				if _, ok := readerSymbols[symbol]; !ok {
+					return nil, fmt.Errorf("cannot resolve Enum %q: reader symbols do not include writer symbol and reader has no default: %q", r.typeName, symbol)
+				}
+			}
+		}
+		return resolveEnum(w, r), nil
+	case "fixed":
+		if w.kind != "fixed" || !namesMatch(w, r) {
+			break
+		}
+		if w.size != r.size {
+			return nil, fmt.Errorf("cannot resolve Fixed %q: writer size %d ought to equal reader size %d", r.typeName, w.size, r.size)
+		}
+		return w.binaryDecoder, nil
+	case "record":
+		if w.kind != "record" || !namesMatch(w, r) {
+			break
+		}
+		return rs.resolveRecord(w, r)
+	default:
+		if decoder := resolvePrimitive(w, r); decoder != nil {
+			return decoder, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve writer type %q to reader type %q", w.typeName, r.typeName)
+}
+
+// namesMatch returns true when the writer named type has the same unqualified name as the reader
+// named type, or as one of the reader's aliases.
+func namesMatch(w, r *Codec) bool {
+	short := w.typeName.short()
+	if short == r.typeName.short() {
+		return true
+	}
+	for _, alias := range r.aliases {
+		if alias == w.typeName.fullName || shortName(alias) == short {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePrimitive returns the decoder for the writer primitive type, converting values to the
+// reader primitive type when the writer type may be promoted to it.  It returns nil when the writer
+// type cannot be resolved to the reader type.
+func resolvePrimitive(w, r *Codec) func([]byte) (interface{}, []byte, error) {
+	if w.kind == r.kind {
+		return w.binaryDecoder
+	}
+
+	var convert func(interface{}) interface{}
+
+	switch w.kind + "->" + r.kind {
+	case "int->long":
+		convert = func(v interface{}) interface{} { return int64(v.(int32)) }
+	case "int->float":
+		convert = func(v interface{}) interface{} { return float32(v.(int32)) }
+	case "int->double":
+		convert = func(v interface{}) interface{} { return float64(v.(int32)) }
+	case "long->float":
+		convert = func(v interface{}) interface{} { return float32(v.(int64)) }
+	case "long->double":
+		convert = func(v interface{}) interface{} { return float64(v.(int64)) }
+	case "float->double":
+		convert = func(v interface{}) interface{} { return float64(v.(float32)) }
+	case "string->bytes":
+		convert = func(v interface{}) interface{} { return []byte(v.(string)) }
+	case "bytes->string":
+		convert = func(v interface{}) interface{} { return string(v.([]byte)) }
+	default:
+		return nil
+	}
+
+	decoder := w.binaryDecoder
+	return func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := decoder(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+		return convert(value), buf, nil
+	}
+}
+
+// resolveEnum returns a decoder that maps each writer symbol to the reader symbol of the same name,
+// or to the reader's default symbol when the reader does not have that symbol.
+func resolveEnum(w, r *Codec) func([]byte) (interface{}, []byte, error) {
+	readerSymbols := make(map[string]struct{}, len(r.symbols))
+	for _, symbol := range r.symbols {
+		readerSymbols[symbol] = struct{}{}
+	}
+	symbols := make([]string, len(w.symbols)) // reader symbol for each writer index; empty when none
+	for i, symbol := range w.symbols {
+		if _, ok := readerSymbols[symbol]; ok {
+			symbols[i] = symbol
+		} else {
+			symbols[i] = r.symbolDefault
+		}
+	}
+
+	return func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := longDecoder(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode Enum %q: index: %s", w.typeName, err)
+		}
+		index := value.(int64) // longDecoder always returns int64
+		if index < 0 || index >= int64(len(symbols)) {
+			return nil, buf, fmt.Errorf("cannot decode Enum %q: index ought to be between 0 and %d; read index: %d", w.typeName, len(symbols)-1, index)
+		}
+		if symbols[index] == "" {
+			return nil, buf, fmt.Errorf("cannot resolve Enum %q: reader symbols do not include writer symbol and reader has no default: %q", r.typeName, w.symbols[index])
+		}
+		return symbols[index], buf, nil
+	}
+}
+
+// resolveRecord returns a decoder that reads each writer field, storing its value under the reader
+// field of the same name, or skipping it when the reader has no such field.  Reader fields that
+// are not in the writer schema are filled with their default values.
+func (rs *resolver) resolveRecord(w, r *Codec) (func([]byte) (interface{}, []byte, error), error) {
+	key := [2]*Codec{w, r}
+	if p, ok := rs.records[key]; ok {
+		// NOTE: Recursive record, so use the decoder that is still being built.
+		return func(buf []byte) (interface{}, []byte, error) {
+			return (*p)(buf)
+		}, nil
+	}
+	var decoder func([]byte) (interface{}, []byte, error)
+	rs.records[key] = &decoder
+
+	type fieldStep struct {
+		name    string // name of reader field, or empty when writer field is skipped
+		decoder func([]byte) (interface{}, []byte, error)
+		skipper func([]byte) ([]byte, error) // skips writer field without decoding it
+	}
+	steps := make([]fieldStep, len(w.fields))
+	found := make(map[string]struct{}, len(w.fields))
+
+	for i, rf := range matchFields(w, r) {
+		wf := w.fields[i]
+		if rf == nil {
+			steps[i] = fieldStep{skipper: wf.codec.binarySkipper}
+			continue
+		}
+		fieldDecoder, err := rs.resolve(wf.codec, rf.codec)
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %q: %s", r.typeName, rf.name, err)
+		}
+		steps[i] = fieldStep{name: rf.name, decoder: fieldDecoder}
+		found[rf.name] = struct{}{}
+	}
+
+	var defaults []*recordField // reader fields not in writer schema
+	for _, rf := range r.fields {
+		if _, ok := found[rf.name]; ok {
+			continue
+		}
+		if !rf.hasDefault {
+			return nil, fmt.Errorf("cannot resolve Record %q: reader field %q ought to have default value when not in writer schema", r.typeName, rf.name)
+		}
+		defaults = append(defaults, rf)
+	}
+
+	decoder = func(buf []byte) (interface{}, []byte, error) {
+		recordMap := make(map[string]interface{}, len(r.fields))
+		for _, step := range steps {
+			var value interface{}
+			var err error
+			if step.skipper != nil {
+				if buf, err = step.skipper(buf); err != nil {
+					return nil, buf, err
+				}
+				continue
+			}
+			if value, buf, err = step.decoder(buf); err != nil {
+				return nil, buf, err
+			}
+			if step.name != "" {
+				recordMap[step.name] = value
+			}
+		}
+		for _, rf := range defaults {
+			// NOTE: Decode the default value each time, so the caller may modify the returned
+			// value without modifying the default value of other records.
+			value, err := decodeDefault(rf.codec, rf.defaultValue)
+			if err != nil {
+				return nil, buf, err
+			}
+			recordMap[rf.name] = value
+		}
+		return recordMap, buf, nil
+	}
+	return decoder, nil
+}
+
+// matchFields returns the reader field matching each writer field, or nil for each writer field
+// the reader does not have.  A writer field matches the reader field of the same name, or else the
+// reader field having an alias of that name, unless that reader field matched an earlier writer
+// field.
+func matchFields(w, r *Codec) []*recordField {
+	readerFields := make(map[string]*recordField, len(r.fields))
+	for _, field := range r.fields {
+		for _, alias := range field.aliases {
+			if _, ok := readerFields[alias]; !ok {
+				readerFields[alias] = field
+			}
+		}
+	}
+	for _, field := range r.fields {
+		readerFields[field.name] = field
+	}
+
+	matches := make([]*recordField, len(w.fields))
+	found := make(map[string]struct{}, len(w.fields))
+	for i, wf := range w.fields {
+		rf, ok := readerFields[wf.name]
+		if !ok {
+			continue
+		}
+		if _, matched := found[rf.name]; matched {
+			continue
+		}
+		matches[i] = rf
+		found[rf.name] = struct{}{}
+	}
+	return matches
+}
+
+// resolveWriterUnion returns a decoder that reads the writer union index, and resolves the writer
+// member type at that index to the reader type.  Writer members that cannot be resolved to the
+// reader type only cause an error when a value of that member is decoded.
+func (rs *resolver) resolveWriterUnion(w, r *Codec) (func([]byte) (interface{}, []byte, error), error) {
+	decoders := make([]func([]byte) (interface{}, []byte, error), len(w.members))
+	errs := make([]error, len(w.members))
+	var resolved int
+	for i, member := range w.members {
+		if decoders[i], errs[i] = rs.resolve(member, r); errs[i] == nil {
+			resolved++
+		}
+	}
+	if resolved == 0 {
+		return nil, fmt.Errorf("cannot resolve any writer Union member to reader type %q: %s", r.typeName, errs[0])
+	}
+	if rs.strict && resolved < len(errs) {
+		for i, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("cannot resolve Union item %d: %s", i+1, err)
+			}
+		}
+	}
+
+	return func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := longDecoder(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+		index := value.(int64) // longDecoder always returns int64
+		if index < 0 || index >= int64(len(decoders)) {
+			return nil, buf, fmt.Errorf("cannot decode Union: index ought to be between 0 and %d; read index: %d", len(decoders)-1, index)
+		}
+		if errs[index] != nil {
+			return nil, buf, fmt.Errorf("cannot resolve Union item %d: %s", index+1, errs[index])
+		}
+		return decoders[index](buf)
+	}, nil
+}
+
+// resolveReaderUnion returns a decoder that reads a non-union writer value as the first reader
+// union member that matches the writer type, preferring a member of the same type over one to which
+// the writer type may be promoted.
+func (rs *resolver) resolveReaderUnion(w, r *Codec) (func([]byte) (interface{}, []byte, error), error) {
+	member := readerUnionMember(w, r)
+	if member == nil {
+		return nil, fmt.Errorf("cannot resolve writer type %q to any reader Union member", w.typeName)
+	}
+	decoder, err := rs.resolve(w, member)
+	if err != nil {
+		return nil, err
+	}
+	memberName := member.typeName.fullName
+	return func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := decoder(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+		if value == nil {
+			return nil, buf, nil
+		}
+		return map[string]interface{}{memberName: value}, buf, nil
+	}, nil
+}
+
+// readerUnionMember returns the first reader union member that matches the non-union writer type,
+// preferring a member of the same type over one to which the writer type may be promoted, or nil
+// when no member matches.
+func readerUnionMember(w, r *Codec) *Codec {
+	for _, exact := range []bool{true, false} {
+		for _, member := range r.members {
+			if unionMemberMatches(w, member, exact) {
+				return member
+			}
+		}
+	}
+	return nil
+}
+
+// unionMemberMatches returns true when the writer type matches the reader union member.  When
+// exact is true, the types must be the same; otherwise the writer type must be able to be promoted
+// to the reader type.
+func unionMemberMatches(w, member *Codec, exact bool) bool {
+	if w.underlying != nil {
+		w = w.underlying
+	}
+	if member.underlying != nil {
+		member = member.underlying
+	}
+	if exact {
+		switch w.kind {
+		case "enum", "fixed", "record":
+			return w.kind == member.kind && namesMatch(w, member)
+		default:
+			return w.kind == member.kind
+		}
+	}
+	return resolvePrimitive(w, member) != nil
+}
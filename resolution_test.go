@@ -0,0 +1,207 @@
+package goavro_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func testResolveInvalid(t *testing.T, writerSchema, readerSchema, errorMessage string) {
+	_, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err == nil || !strings.Contains(err.Error(), errorMessage) {
+		t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+	}
+}
+
+func testResolvePass(t *testing.T, writerSchema, readerSchema string, datum, expected interface{}) {
+	writer, err := goavro.NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryEncode(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, buf, err := reader.BinaryDecode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, want := fmt.Sprintf("%v", value), fmt.Sprintf("%v", expected); actual != want {
+		t.Errorf("Actual: %v; Expected: %v", actual, want)
+	}
+	if len(buf) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", buf, nil)
+	}
+}
+
+func testResolveDecodeFail(t *testing.T, writerSchema, readerSchema string, datum interface{}, errorMessage string) {
+	writer, err := goavro.NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryEncode(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, newBuffer, err := reader.BinaryDecode(buf)
+	if err == nil || !strings.Contains(err.Error(), errorMessage) {
+		t.Errorf("Actual: %v; Expected: %s", err, errorMessage)
+	}
+	if value != nil {
+		t.Errorf("Actual: %v; Expected: %v", value, nil)
+	}
+	if !bytes.Equal(buf, newBuffer) {
+		t.Errorf("Actual: %v; Expected: %v", newBuffer, buf)
+	}
+}
+
+func TestResolveInvalidSchema(t *testing.T) {
+	testResolveInvalid(t, `"integer"`, `"int"`, "cannot create codec for writer schema")
+	testResolveInvalid(t, `"int"`, `"integer"`, "cannot create codec for reader schema")
+}
+
+func TestResolvePrimitives(t *testing.T) {
+	testResolvePass(t, `"int"`, `"int"`, int32(3), int32(3))
+	testResolvePass(t, `"string"`, `"string"`, "hello", "hello")
+	testResolveInvalid(t, `"long"`, `"int"`, `cannot resolve writer type "long" to reader type "int"`)
+	testResolveInvalid(t, `"string"`, `"int"`, `cannot resolve writer type "string" to reader type "int"`)
+	testResolveInvalid(t, `"double"`, `"float"`, `cannot resolve writer type "double" to reader type "float"`)
+}
+
+func TestResolvePromotion(t *testing.T) {
+	testResolvePass(t, `"int"`, `"long"`, int32(-3), int64(-3))
+	testResolvePass(t, `"int"`, `"float"`, int32(-3), float32(-3))
+	testResolvePass(t, `"int"`, `"double"`, int32(-3), float64(-3))
+	testResolvePass(t, `"long"`, `"float"`, int64(5), float32(5))
+	testResolvePass(t, `"long"`, `"double"`, int64(5), float64(5))
+	testResolvePass(t, `"float"`, `"double"`, float32(1.5), float64(1.5))
+	testResolvePass(t, `"string"`, `"bytes"`, "abc", []byte("abc"))
+	testResolvePass(t, `"bytes"`, `"string"`, []byte("abc"), "abc")
+}
+
+func TestResolveArrayAndMap(t *testing.T) {
+	testResolvePass(t, `{"type":"array","items":"int"}`, `{"type":"array","items":"long"}`, []interface{}{int32(1), int32(2)}, []interface{}{int64(1), int64(2)})
+	testResolvePass(t, `{"type":"map","values":"float"}`, `{"type":"map","values":"double"}`, map[string]interface{}{"a": float32(1.5)}, map[string]interface{}{"a": float64(1.5)})
+	testResolveInvalid(t, `{"type":"array","items":"string"}`, `{"type":"array","items":"int"}`, "Array items: cannot resolve")
+	testResolveInvalid(t, `{"type":"map","values":"string"}`, `{"type":"array","items":"string"}`, "cannot resolve writer type")
+}
+
+func TestResolveEnum(t *testing.T) {
+	testResolvePass(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`, `{"type":"enum","name":"e1","symbols":["charlie","alpha"]}`, "alpha", "alpha")
+	testResolvePass(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, `{"type":"enum","name":"e1","symbols":["alpha","unknown"],"default":"unknown"}`, "bravo", "unknown")
+	testResolveDecodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, `{"type":"enum","name":"e1","symbols":["alpha"]}`, "bravo", `reader symbols do not include writer symbol and reader has no default: "bravo"`)
+	testResolveInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha"]}`, `{"type":"enum","name":"e2","symbols":["alpha"]}`, "cannot resolve writer type")
+}
+
+func TestResolveFixed(t *testing.T) {
+	testResolvePass(t, `{"type":"fixed","name":"com.example.f1","size":2}`, `{"type":"fixed","name":"org.example.f1","size":2}`, []byte("ab"), []byte("ab"))
+	testResolveInvalid(t, `{"type":"fixed","name":"f1","size":2}`, `{"type":"fixed","name":"f1","size":3}`, "writer size 2 ought to equal reader size 3")
+	testResolveInvalid(t, `{"type":"fixed","name":"f1","size":2}`, `{"type":"fixed","name":"f2","size":2}`, "cannot resolve writer type")
+}
+
+func TestResolveRecord(t *testing.T) {
+	writerSchema := `{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"skipped","type":{"type":"array","items":"string"}},{"name":"b","type":"string"}]}`
+
+	// reordered fields, skipped writer field, promoted field, and reader field with default value
+	readerSchema := `{"type":"record","name":"r1","fields":[{"name":"b","type":"bytes"},{"name":"a","type":"long"},{"name":"c","type":"double","default":2.5}]}`
+	testResolvePass(t, writerSchema, readerSchema,
+		map[string]interface{}{"a": int32(1), "skipped": []interface{}{"x", "y"}, "b": "bee"},
+		map[string]interface{}{"a": int64(1), "b": []byte("bee"), "c": float64(2.5)})
+
+	testResolveInvalid(t, writerSchema, `{"type":"record","name":"r1","fields":[{"name":"c","type":"double"}]}`, `reader field "c" ought to have default value when not in writer schema`)
+	testResolveInvalid(t, writerSchema, `{"type":"record","name":"r1","fields":[{"name":"a","type":"string"}]}`, `Record "r1" field "a": cannot resolve`)
+	testResolveInvalid(t, writerSchema, `{"type":"record","name":"r2","fields":[{"name":"a","type":"int"}]}`, "cannot resolve writer type")
+}
+
+func TestResolveRecordSkipsBlocks(t *testing.T) {
+	writerSchema := `{"type":"record","name":"r1","fields":[{"name":"skipped","type":{"type":"array","items":"string"}},{"name":"a","type":"int"}]}`
+	readerSchema := `{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`
+	reader, err := goavro.NewResolvingCodec(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: Skipped writer field is an array whose block has negative count followed by its byte
+	// size, which is used to jump over the block.  Its bytes are invalid, so decoding it would fail.
+	buf := goavro.AppendLong(nil, -1)
+	buf = goavro.AppendLong(buf, 2)
+	buf = append(buf, 0xff, 0xff)
+	buf = goavro.AppendLong(buf, 0)
+	buf = goavro.AppendInt(buf, 3)
+
+	value, rest, err := reader.BinaryDecode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", value), "map[a:3]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Actual: %v; Expected: %v", rest, nil)
+	}
+}
+
+func TestResolveRecordRecursive(t *testing.T) {
+	writerSchema := `{"type":"record","name":"LongList","fields":[{"name":"value","type":"int"},{"name":"next","type":["null","LongList"]}]}`
+	readerSchema := `{"type":"record","name":"LongList","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","LongList"]}]}`
+	testResolvePass(t, writerSchema, readerSchema,
+		map[string]interface{}{"value": int32(1), "next": goavro.Union("LongList", map[string]interface{}{"value": int32(2), "next": nil})},
+		map[string]interface{}{"value": int64(1), "next": map[string]interface{}{"LongList": map[string]interface{}{"value": int64(2), "next": nil}}})
+}
+
+func TestResolveUnion(t *testing.T) {
+	// writer union to reader union
+	testResolvePass(t, `["null","int"]`, `["null","string","long"]`, goavro.Union("int", int32(3)), map[string]interface{}{"long": int64(3)})
+	testResolvePass(t, `["null","int"]`, `["null","string","long"]`, nil, nil)
+
+	// non-union writer to reader union prefers exact match
+	testResolvePass(t, `"int"`, `["null","long","int"]`, int32(3), map[string]interface{}{"int": int32(3)})
+	testResolvePass(t, `"int"`, `["null","long"]`, int32(3), map[string]interface{}{"long": int64(3)})
+	testResolveInvalid(t, `"string"`, `["null","long"]`, "cannot resolve writer type \"string\" to any reader Union member")
+
+	// writer union to non-union reader
+	testResolvePass(t, `["int","long"]`, `"long"`, goavro.Union("int", int32(3)), int64(3))
+	testResolveDecodeFail(t, `["int","string"]`, `"long"`, goavro.Union("string", "x"), "cannot resolve Union item 2")
+	testResolveInvalid(t, `["string","bytes"]`, `"long"`, "cannot resolve any writer Union member")
+}
+
+func TestResolveLogicalType(t *testing.T) {
+	testResolvePass(t, `"long"`, `{"type":"long","logicalType":"timestamp-millis"}`, int64(0), "1970-01-01 00:00:00 +0000 UTC")
+	testResolvePass(t, `{"type":"int","logicalType":"date"}`, `"long"`, int32(1), int64(1))
+}
+
+func TestResolveAliases(t *testing.T) {
+	testSchemaInvalid(t, `{"type":"fixed","name":"f1","size":2,"aliases":"f0"}`, "schema aliases, if provided, ought to be array of strings")
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha"],"aliases":[""]}`, "schema alias 1 ought to be non-empty string")
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha"],"aliases":["&e0"]}`, "schema alias 1: schema name ought to start with")
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"fixed","name":"f2","size":1,"aliases":["r1"]}}]}`, `schema alias ought to be unique name: "r1"`)
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","aliases":["&f0"]}]}`, `Record "r1" field "f1" ought to have valid aliases`)
+
+	// named type may be referred to by its alias
+	testSchemaValid(t, `{"type":"record","name":"com.example.r1","aliases":["r0"],"fields":[{"name":"next","type":["null","com.example.r0"]}]}`)
+
+	// renamed types
+	testResolvePass(t, `{"type":"enum","name":"com.example.e0","symbols":["alpha"]}`, `{"type":"enum","name":"com.example.e1","aliases":["e0"],"symbols":["alpha"]}`, "alpha", "alpha")
+	testResolvePass(t, `{"type":"fixed","name":"f0","size":1}`, `{"type":"fixed","name":"f1","aliases":["f0"],"size":1}`, []byte("a"), []byte("a"))
+
+	// renamed record and fields
+	writerSchema := `{"type":"record","name":"r0","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"}]}`
+	readerSchema := `{"type":"record","name":"r1","aliases":["r0"],"fields":[{"name":"alpha","type":"long","aliases":["a"]},{"name":"b","type":"string"}]}`
+	testResolvePass(t, writerSchema, readerSchema,
+		map[string]interface{}{"a": int32(1), "b": "bee"},
+		map[string]interface{}{"alpha": int64(1), "b": "bee"})
+
+	// field name takes precedence over alias of another field
+	readerSchema = `{"type":"record","name":"r0","fields":[{"name":"alpha","type":"string","aliases":["b"]},{"name":"b","type":"string"},{"name":"a","type":"int"}]}`
+	testResolveInvalid(t, writerSchema, readerSchema, `reader field "alpha" ought to have default value`)
+}
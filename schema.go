@@ -0,0 +1,219 @@
+package goavro
+
+// Schema is the interface implemented by each node of a schema tree, as returned by
+// Codec.Schema and ParseSchema.  The concrete type of each node is one of *PrimitiveSchema,
+// *RecordSchema, *EnumSchema, *FixedSchema, *ArraySchema, *MapSchema, *UnionSchema, or
+// *LogicalSchema.
+//
+// Named types are represented by a single node, however many times the schema refers to them, so
+// the tree of a recursive schema contains cycles.  Programs that walk the tree ought to keep track
+// of the named types they have already visited.
+type Schema interface {
+	// Type returns the Avro type of the schema, such as "int", "record", or "union".  For a
+	// LogicalSchema, it returns the type of its underlying schema.
+	Type() string
+}
+
+// PrimitiveSchema describes one of the Avro primitive types.
+type PrimitiveSchema struct {
+	Name string // "null", "boolean", "int", "long", "float", "double", "bytes", or "string"
+}
+
+// Type returns the name of the primitive type.
+func (s *PrimitiveSchema) Type() string { return s.Name }
+
+// RecordSchema describes an Avro record.
+type RecordSchema struct {
+	Name    string   // full name, including namespace
+	Doc     string   // documentation, if any
+	Aliases []string // full names of aliases, if any
+	Fields  []*Field
+}
+
+// Type returns "record".
+func (s *RecordSchema) Type() string { return "record" }
+
+// Field describes one field of an Avro record.
+type Field struct {
+	Name       string
+	Doc        string      // documentation, if any
+	Type       Schema      // schema of the field value
+	HasDefault bool        // true when the field has a default value
+	Default    interface{} // default value, as unmarshaled from the JSON schema
+	Order      string      // sort order: "ascending", "descending", or "ignore"
+	Aliases    []string    // aliases, if any
+}
+
+// EnumSchema describes an Avro enum.
+type EnumSchema struct {
+	Name    string   // full name, including namespace
+	Doc     string   // documentation, if any
+	Aliases []string // full names of aliases, if any
+	Symbols []string
+	Default string // symbol used when resolving an unknown symbol, if any
+}
+
+// Type returns "enum".
+func (s *EnumSchema) Type() string { return "enum" }
+
+// FixedSchema describes an Avro fixed type.
+type FixedSchema struct {
+	Name    string   // full name, including namespace
+	Aliases []string // full names of aliases, if any
+	Size    int
+}
+
+// Type returns "fixed".
+func (s *FixedSchema) Type() string { return "fixed" }
+
+// ArraySchema describes an Avro array.
+type ArraySchema struct {
+	Items Schema
+}
+
+// Type returns "array".
+func (s *ArraySchema) Type() string { return "array" }
+
+// MapSchema describes an Avro map.
+type MapSchema struct {
+	Values Schema
+}
+
+// Type returns "map".
+func (s *MapSchema) Type() string { return "map" }
+
+// UnionSchema describes an Avro union.
+type UnionSchema struct {
+	Members []Schema
+}
+
+// Type returns "union".
+func (s *UnionSchema) Type() string { return "union" }
+
+// LogicalSchema describes a logical type annotating an underlying schema.
+type LogicalSchema struct {
+	Name       string                 // value of the logicalType attribute, such as "decimal"
+	Underlying Schema                 // schema of the annotated type
+	Properties map[string]interface{} // other attributes of the logical type, such as precision and scale
+}
+
+// Type returns the type of the underlying schema.
+func (s *LogicalSchema) Type() string { return s.Underlying.Type() }
+
+// ParseSchema parses the schema specification, and returns its schema tree.  It returns an error
+// when the specification is not a valid Avro schema.
+func ParseSchema(schemaSpecification string) (Schema, error) {
+	c, err := NewCodec(schemaSpecification)
+	if err != nil {
+		return nil, err
+	}
+	return c.Schema(), nil
+}
+
+// Schema returns the schema tree of the Codec's schema.
+func (c Codec) Schema() Schema {
+	cp := &c
+	switch c.kind {
+	case "enum", "fixed", "record":
+		// NOTE: Use the codec registered in the symbol table rather than this copy of it, so
+		// references back to a recursive type resolve to the same node.
+		if registered, ok := c.symbolTable[c.typeName.fullName]; ok {
+			cp = registered
+		}
+	}
+	return schemaFromCodec(cp, make(map[*Codec]Schema))
+}
+
+// schemaFromCodec returns the schema tree of the codec.  The named map stores the node already
+// created for each named type codec.
+func schemaFromCodec(c *Codec, named map[*Codec]Schema) Schema {
+	if s, ok := named[c]; ok {
+		return s
+	}
+
+	if c.underlying != nil {
+		s := &LogicalSchema{Name: c.logicalType}
+		if c.logicalProperties != nil {
+			s.Properties = copyValue(c.logicalProperties).(map[string]interface{})
+		}
+		switch c.kind {
+		case "enum", "fixed", "record":
+			// NOTE: Named type codecs annotated with a logical type are registered in the symbol
+			// table, so register the node before building the underlying node, which may refer
+			// back to this codec.
+			named[c] = s
+		}
+		s.Underlying = schemaFromCodec(c.underlying, named)
+		return s
+	}
+
+	switch c.kind {
+	case "array":
+		return &ArraySchema{Items: schemaFromCodec(c.items, named)}
+	case "map":
+		return &MapSchema{Values: schemaFromCodec(c.items, named)}
+	case "union":
+		s := &UnionSchema{Members: make([]Schema, len(c.members))}
+		for i, member := range c.members {
+			s.Members[i] = schemaFromCodec(member, named)
+		}
+		return s
+	case "enum":
+		s := &EnumSchema{Name: c.typeName.fullName, Doc: c.doc, Aliases: copyStrings(c.aliases), Symbols: copyStrings(c.symbols), Default: c.symbolDefault}
+		named[c] = s
+		return s
+	case "fixed":
+		s := &FixedSchema{Name: c.typeName.fullName, Aliases: copyStrings(c.aliases), Size: c.size}
+		named[c] = s
+		return s
+	case "record":
+		s := &RecordSchema{Name: c.typeName.fullName, Doc: c.doc, Aliases: copyStrings(c.aliases)}
+		named[c] = s // register before fields, which may refer back to this record
+		s.Fields = make([]*Field, len(c.fields))
+		for i, field := range c.fields {
+			s.Fields[i] = &Field{
+				Name:       field.name,
+				Doc:        field.doc,
+				Type:       schemaFromCodec(field.codec, named),
+				HasDefault: field.hasDefault,
+				Default:    copyValue(field.defaultValue),
+				Order:      field.order,
+				Aliases:    copyStrings(field.aliases),
+			}
+		}
+		return s
+	default:
+		return &PrimitiveSchema{Name: c.kind}
+	}
+}
+
+// copyStrings returns a copy of the slice, so callers may not modify the codec's slice.
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
+
+// copyValue returns a deep copy of the value unmarshaled from the JSON schema, so callers may not
+// modify the codec's maps, slices, and byte slices.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyValue(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = copyValue(item)
+		}
+		return s
+	case []byte:
+		return append([]byte(nil), v...)
+	default:
+		return value
+	}
+}
@@ -1,7 +1,10 @@
 package goavro_test
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/karrick/goavro"
 )
 
 // NOTE: This file includes test cases that apply to more than one non-primitive data type.
@@ -132,3 +135,130 @@ func TestMapValueTypeRecord(t *testing.T) {
 	// cannot decode because order of map key enumeration random, and records are returned as a Go map
 	testBinaryEncodePass(t, schema, datum, expected)
 }
+
+func TestSchemaTree(t *testing.T) {
+	schema, err := goavro.ParseSchema(`{
+  "type": "record",
+  "name": "User",
+  "namespace": "com.acme",
+  "doc": "a user",
+  "aliases": ["Person"],
+  "fields": [
+    {"name": "id", "type": "long", "doc": "identifier", "order": "descending"},
+    {"name": "email", "type": ["null", "string"], "default": null, "aliases": ["mail"]},
+    {"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"], "default": "A"}},
+    {"name": "hash", "type": {"type": "fixed", "name": "md5", "size": 16}},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "attrs", "type": {"type": "map", "values": "int"}},
+    {"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}}
+  ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, ok := schema.(*goavro.RecordSchema)
+	if !ok {
+		t.Fatalf("Actual: %T; Expected: %T", schema, record)
+	}
+	if actual, expected := fmt.Sprintf("%s %s %s %v %d", record.Type(), record.Name, record.Doc, record.Aliases, len(record.Fields)), "record com.acme.User a user [com.acme.Person] 8"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	id := record.Fields[0]
+	if actual, expected := fmt.Sprintf("%s %s %s %s %v", id.Name, id.Type.Type(), id.Doc, id.Order, id.HasDefault), "id long identifier descending false"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	email := record.Fields[1]
+	union, ok := email.Type.(*goavro.UnionSchema)
+	if !ok {
+		t.Fatalf("Actual: %T; Expected: %T", email.Type, union)
+	}
+	if actual, expected := fmt.Sprintf("%s %s %s %v %v %v", union.Members[0].Type(), union.Members[1].Type(), email.Order, email.HasDefault, email.Default, email.Aliases), "null string ascending true <nil> [mail]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	enum := record.Fields[2].Type.(*goavro.EnumSchema)
+	if actual, expected := fmt.Sprintf("%s %v %s", enum.Name, enum.Symbols, enum.Default), "com.acme.Kind [A B] A"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	fixed := record.Fields[3].Type.(*goavro.FixedSchema)
+	if actual, expected := fmt.Sprintf("%s %d", fixed.Name, fixed.Size), "com.acme.md5 16"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	if actual, expected := record.Fields[4].Type.(*goavro.ArraySchema).Items.Type(), "string"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := record.Fields[5].Type.(*goavro.MapSchema).Values.Type(), "int"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+
+	created := record.Fields[6].Type.(*goavro.LogicalSchema)
+	if actual, expected := fmt.Sprintf("%s %s %v", created.Name, created.Type(), created.Properties), "timestamp-millis long map[]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	amount := record.Fields[7].Type.(*goavro.LogicalSchema)
+	if actual, expected := fmt.Sprintf("%s %s %v", amount.Name, amount.Type(), amount.Properties), "decimal bytes map[precision:4 scale:2]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestSchemaTreeRecursive(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"record","name":"LongList","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","LongList"]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := c.Schema().(*goavro.RecordSchema)
+	next := record.Fields[1].Type.(*goavro.UnionSchema).Members[1]
+	if next != goavro.Schema(record) {
+		t.Errorf("Actual: %p; Expected: %p", next, record)
+	}
+}
+
+func TestSchemaTreeNamedLogicalType(t *testing.T) {
+	schema, err := goavro.ParseSchema(`{"type":"fixed","name":"money","size":8,"logicalType":"decimal","precision":10,"scale":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logical := schema.(*goavro.LogicalSchema)
+	fixed := logical.Underlying.(*goavro.FixedSchema)
+	if actual, expected := fmt.Sprintf("%s %s %d %v", logical.Name, fixed.Name, fixed.Size, logical.Properties), "decimal money 8 map[precision:10 scale:2]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestSchemaTreeInvalid(t *testing.T) {
+	if _, err := goavro.ParseSchema(`"integer"`); err == nil {
+		t.Errorf("Actual: %v; Expected: %v", err, "error")
+	}
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int","order":"sideways"}]}`, `Record "r1" field "f1" order ought to be ascending, descending, or ignore`)
+}
+
+func TestSchemaTreeCopiesCodecValues(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"record","name":"r","fields":[
+		{"name":"tags","type":{"type":"array","items":"string"},"default":["a"]},
+		{"name":"attrs","type":{"type":"map","values":"int"},"default":{"k":1}},
+		{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":4,"scale":2,"extra":{"k":"v"}}}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modify := func(schema goavro.Schema) {
+		record := schema.(*goavro.RecordSchema)
+		record.Fields[0].Default.([]interface{})[0] = "modified"
+		record.Fields[1].Default.(map[string]interface{})["k"] = "modified"
+		properties := record.Fields[2].Type.(*goavro.LogicalSchema).Properties
+		properties["scale"] = "modified"
+		properties["extra"].(map[string]interface{})["k"] = "modified"
+	}
+	modify(c.Schema())
+
+	record := c.Schema().(*goavro.RecordSchema)
+	properties := record.Fields[2].Type.(*goavro.LogicalSchema).Properties
+	if actual, expected := fmt.Sprintf("%v %v %v", record.Fields[0].Default, record.Fields[1].Default, properties), "[a] map[k:1] map[extra:map[k:v] precision:4 scale:2]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
@@ -0,0 +1,150 @@
+package goavro
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	singleObjectMagic      = "\xC3\x01"
+	singleObjectHeaderSize = len(singleObjectMagic) + 8 // magic followed by 8-byte fingerprint
+)
+
+// SingleObjectResolver is the interface implemented by types that can return the Codec for the
+// writer schema of single-object encoded data, given the 64-bit Rabin fingerprint of that schema.
+type SingleObjectResolver interface {
+	CodecForFingerprint(fingerprint uint64) (*Codec, error)
+}
+
+// SingleObjectCodecs is a SingleObjectResolver that stores writer Codecs by the 64-bit Rabin
+// fingerprint of their schemas.
+type SingleObjectCodecs map[uint64]*Codec
+
+// NewSingleObjectCodecs returns a SingleObjectCodecs that resolves the fingerprints of the
+// provided Codecs.
+func NewSingleObjectCodecs(codecs ...*Codec) SingleObjectCodecs {
+	soc := make(SingleObjectCodecs, len(codecs))
+	for _, c := range codecs {
+		soc[c.Fingerprint()] = c
+	}
+	return soc
+}
+
+// CodecForFingerprint returns the Codec whose schema has the specified fingerprint.
+func (soc SingleObjectCodecs) CodecForFingerprint(fingerprint uint64) (*Codec, error) {
+	c, ok := soc[fingerprint]
+	if !ok {
+		return nil, fmt.Errorf("cannot find writer schema with fingerprint: %#016x", fingerprint)
+	}
+	return c, nil
+}
+
+// SingleObjectEncoder encodes data using the Avro single-object encoding: the two byte marker C3
+// 01, followed by the 8-byte little-endian Rabin fingerprint of the schema, followed by the Avro
+// binary encoding of the data.
+type SingleObjectEncoder struct {
+	codec  *Codec
+	header []byte
+}
+
+// NewSingleObjectEncoder returns a SingleObjectEncoder that encodes data using the provided Codec.
+func NewSingleObjectEncoder(codec *Codec) (*SingleObjectEncoder, error) {
+	if codec == nil {
+		return nil, errors.New("cannot create SingleObjectEncoder without Codec")
+	}
+	header := make([]byte, singleObjectHeaderSize)
+	copy(header, singleObjectMagic)
+	binary.LittleEndian.PutUint64(header[len(singleObjectMagic):], codec.Fingerprint())
+	return &SingleObjectEncoder{codec: codec, header: header}, nil
+}
+
+// Encode appends the single-object encoding of the provided datum to buf.  On success, it returns
+// the new byte slice with the appended bytes.  On error, it returns the original byte slice
+// without any encoded bytes.
+func (soe *SingleObjectEncoder) Encode(buf []byte, datum interface{}) ([]byte, error) {
+	newBuf, err := soe.codec.BinaryEncode(append(buf, soe.header...), datum)
+	if err != nil {
+		return buf, err
+	}
+	return newBuf, nil
+}
+
+// SingleObjectDecoder decodes data encoded using the Avro single-object encoding.  It looks up the
+// writer schema using the fingerprint at the start of the data, and when created with a reader
+// Codec, resolves data from the writer schema to the reader schema.
+type SingleObjectDecoder struct {
+	reader   *Codec
+	resolver SingleObjectResolver
+
+	lock   sync.RWMutex
+	codecs map[uint64]*Codec // codec used to decode data written with each fingerprint
+}
+
+// NewSingleObjectDecoder returns a SingleObjectDecoder that uses the provided resolver to look up
+// writer schemas.  When reader is nil, data is decoded using the writer schema.
+func NewSingleObjectDecoder(resolver SingleObjectResolver, reader *Codec) (*SingleObjectDecoder, error) {
+	if resolver == nil {
+		return nil, errors.New("cannot create SingleObjectDecoder without SingleObjectResolver")
+	}
+	return &SingleObjectDecoder{reader: reader, resolver: resolver, codecs: make(map[uint64]*Codec)}, nil
+}
+
+// Decode decodes single-object encoded data from the provided byte slice.  On success, it returns
+// the decoded value, along with a new byte slice with the decoded bytes consumed.  On error, it
+// returns the original byte slice without any bytes consumed and the error.
+func (sod *SingleObjectDecoder) Decode(buf []byte) (interface{}, []byte, error) {
+	fingerprint, err := SingleObjectFingerprint(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	c, err := sod.codecForFingerprint(fingerprint)
+	if err != nil {
+		return nil, buf, fmt.Errorf("cannot decode single-object encoding: %s", err)
+	}
+	value, newBuf, err := c.BinaryDecode(buf[singleObjectHeaderSize:])
+	if err != nil {
+		return nil, buf, err
+	}
+	return value, newBuf, nil
+}
+
+// codecForFingerprint returns the codec used to decode data written with the schema having the
+// specified fingerprint, creating and storing it the first time the fingerprint is seen.
+func (sod *SingleObjectDecoder) codecForFingerprint(fingerprint uint64) (*Codec, error) {
+	sod.lock.RLock()
+	c, ok := sod.codecs[fingerprint]
+	sod.lock.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	c, err := sod.resolver.CodecForFingerprint(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if sod.reader != nil {
+		if c, err = newResolvingCodec(c, sod.reader); err != nil {
+			return nil, err
+		}
+	}
+
+	sod.lock.Lock()
+	sod.codecs[fingerprint] = c
+	sod.lock.Unlock()
+	return c, nil
+}
+
+// SingleObjectFingerprint returns the writer schema fingerprint of the single-object encoded data
+// in the provided byte slice.
+func SingleObjectFingerprint(buf []byte) (uint64, error) {
+	if len(buf) < singleObjectHeaderSize {
+		return 0, fmt.Errorf("cannot decode single-object encoding: buffer ought to have at least %d bytes; received: %d", singleObjectHeaderSize, len(buf))
+	}
+	if !bytes.HasPrefix(buf, []byte(singleObjectMagic)) {
+		return 0, fmt.Errorf("cannot decode single-object encoding: marker ought to be %#v; received: %#v", []byte(singleObjectMagic), buf[:len(singleObjectMagic)])
+	}
+	return binary.LittleEndian.Uint64(buf[len(singleObjectMagic):]), nil
+}
@@ -0,0 +1,136 @@
+package goavro_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestSingleObjectEncode(t *testing.T) {
+	c, err := goavro.NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soe, err := goavro.NewSingleObjectEncoder(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := soe.Encode([]byte("prefix"), int32(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fingerprint of "int" is 0x7275d51a3f395c8f
+	expected := []byte("prefix\xC3\x01\x8f\x5c\x39\x3f\x1a\xd5\x75\x72\x06")
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %#v; Expected: %#v", buf, expected)
+	}
+
+	buf, err = soe.Encode([]byte("prefix"), "not an int")
+	if err == nil {
+		t.Errorf("Actual: %v; Expected: %v", err, "error")
+	}
+	if actual, expected := string(buf), "prefix"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestSingleObjectDecodeWithWriterSchema(t *testing.T) {
+	c, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"string"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soe, err := goavro.NewSingleObjectEncoder(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := soe.Encode(nil, map[string]interface{}{"a": "alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sod, err := goavro.NewSingleObjectDecoder(goavro.NewSingleObjectCodecs(c), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, buf, err := sod.Decode(append(buf, "suffix"...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", value), "map[a:alpha]"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := string(buf), "suffix"; actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestSingleObjectDecodeWithReaderSchema(t *testing.T) {
+	writer, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bravo"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soe, err := goavro.NewSingleObjectEncoder(writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := soe.Encode(nil, map[string]interface{}{"a": int32(13)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sod, err := goavro.NewSingleObjectDecoder(goavro.NewSingleObjectCodecs(writer), reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ { // second time uses stored codec
+		value, rest, err := sod.Decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := fmt.Sprintf("%v", value), "map[a:13 b:bravo]"; actual != expected {
+			t.Errorf("Actual: %v; Expected: %v", actual, expected)
+		}
+		if len(rest) != 0 {
+			t.Errorf("Actual: %v; Expected: %v", rest, nil)
+		}
+	}
+}
+
+func TestSingleObjectDecodeFail(t *testing.T) {
+	c, err := goavro.NewCodec(`"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sod, err := goavro.NewSingleObjectDecoder(goavro.NewSingleObjectCodecs(c), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		buf          []byte
+		errorMessage string
+	}{
+		{[]byte("\xC3\x01\x00"), "buffer ought to have at least 10 bytes"},
+		{[]byte("\xC3\x02\x00\x00\x00\x00\x00\x00\x00\x00"), "marker ought to be"},
+		{[]byte("\xC3\x01\x00\x00\x00\x00\x00\x00\x00\x00"), "cannot find writer schema with fingerprint"},
+	}
+	for _, tc := range cases {
+		value, buf, err := sod.Decode(tc.buf)
+		if err == nil || !strings.Contains(err.Error(), tc.errorMessage) {
+			t.Errorf("Actual: %v; Expected: %s", err, tc.errorMessage)
+		}
+		if value != nil {
+			t.Errorf("Actual: %v; Expected: %v", value, nil)
+		}
+		if !bytes.Equal(buf, tc.buf) {
+			t.Errorf("Actual: %v; Expected: %v", buf, tc.buf)
+		}
+	}
+}
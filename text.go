@@ -0,0 +1,385 @@
+package goavro
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NOTE: The functions in this file scan and emit the JSON text encoding described by the Avro
+// specification.  They are purposely written to operate on byte slices the same way the binary
+// decoders and encoders do, consuming bytes from the front of the provided slice, or appending
+// bytes to the end of the provided slice.
+
+// advanceToNonWhitespace consumes bytes from buf until a non-whitespace character is found.  It
+// returns io.ErrShortBuffer when no more bytes remain, because its purpose is to scan ahead to the
+// next character, and the absence of another character is an error.
+func advanceToNonWhitespace(buf []byte) ([]byte, error) {
+	for i, b := range buf {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			// skip insignificant whitespace
+		default:
+			return buf[i:], nil
+		}
+	}
+	return nil, io.ErrShortBuffer
+}
+
+// advanceAndConsume advances to the next non-whitespace character and consumes it when it matches
+// the expected byte; otherwise it returns an error.
+func advanceAndConsume(buf []byte, expected byte) ([]byte, error) {
+	var err error
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return nil, err
+	}
+	if buf[0] != expected {
+		return nil, fmt.Errorf("expected: %q; actual: %q", expected, buf[0])
+	}
+	return buf[1:], nil
+}
+
+// consumeLiteral advances to the next non-whitespace character and consumes the provided literal,
+// for instance `null` or `true`.
+func consumeLiteral(buf []byte, literal string) ([]byte, error) {
+	var err error
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return nil, err
+	}
+	if len(buf) < len(literal) {
+		return nil, io.ErrShortBuffer
+	}
+	if string(buf[:len(literal)]) != literal {
+		return nil, fmt.Errorf("expected: %q; actual: %q", literal, buf[:len(literal)])
+	}
+	return buf[len(literal):], nil
+}
+
+// hasPrefixLiteral returns true when the next non-whitespace characters in buf spell the provided
+// literal.
+func hasPrefixLiteral(buf []byte, literal string) bool {
+	buf, err := advanceToNonWhitespace(buf)
+	if err != nil || len(buf) < len(literal) {
+		return false
+	}
+	return string(buf[:len(literal)]) == literal
+}
+
+// textNumberToken returns the characters of the JSON number at the front of buf, along with the
+// remaining bytes.
+func textNumberToken(buf []byte) (string, []byte, error) {
+	var err error
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return "", nil, err
+	}
+	var i int
+	for i < len(buf) && isNumberByte(buf[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", nil, fmt.Errorf("expected: number; actual: %q", buf[0])
+	}
+	return string(buf[:i]), buf[i:], nil
+}
+
+func isNumberByte(b byte) bool {
+	return (b >= '0' && b <= '9') || b == '-' || b == '+' || b == '.' || b == 'e' || b == 'E'
+}
+
+// textDecodeString consumes a JSON string from the front of buf, and returns its unescaped value.
+func textDecodeString(buf []byte) (string, []byte, error) {
+	var err error
+	if buf, err = advanceAndConsume(buf, '"'); err != nil {
+		return "", nil, err
+	}
+	// NOTE: Fast path for strings without any escape sequences.
+	for i, b := range buf {
+		if b == '"' {
+			return string(buf[:i]), buf[i+1:], nil
+		}
+		if b == '\\' || b < 0x20 {
+			break
+		}
+	}
+	var value []byte
+	for i := 0; i < len(buf); i++ {
+		switch b := buf[i]; {
+		case b == '"':
+			return string(value), buf[i+1:], nil
+		case b < 0x20:
+			return "", nil, fmt.Errorf("string: unescaped control character: %q", b)
+		case b != '\\':
+			value = append(value, b)
+		default:
+			i++
+			if i == len(buf) {
+				return "", nil, io.ErrShortBuffer
+			}
+			switch buf[i] {
+			case '"', '\\', '/':
+				value = append(value, buf[i])
+			case 'b':
+				value = append(value, '\b')
+			case 'f':
+				value = append(value, '\f')
+			case 'n':
+				value = append(value, '\n')
+			case 'r':
+				value = append(value, '\r')
+			case 't':
+				value = append(value, '\t')
+			case 'u':
+				r, err := parseUnicodeEscape(buf[i+1:])
+				if err != nil {
+					return "", nil, err
+				}
+				i += 4
+				if utf16.IsSurrogate(r) {
+					// NOTE: Characters outside the Basic Multilingual Plane are escaped
+					// as a pair of UTF-16 surrogates.
+					if len(buf) < i+3 || buf[i+1] != '\\' || buf[i+2] != 'u' {
+						return "", nil, fmt.Errorf("string: unpaired surrogate: %U", r)
+					}
+					r2, err := parseUnicodeEscape(buf[i+3:])
+					if err != nil {
+						return "", nil, err
+					}
+					r1 := r
+					if r = utf16.DecodeRune(r1, r2); r == utf8.RuneError {
+						return "", nil, fmt.Errorf("string: invalid surrogate pair: %U %U", r1, r2)
+					}
+					i += 6
+				}
+				value = utf8.AppendRune(value, r)
+			default:
+				return "", nil, fmt.Errorf("string: invalid escape sequence: %q", buf[i-1:i+1])
+			}
+		}
+	}
+	return "", nil, io.ErrShortBuffer
+}
+
+// parseUnicodeEscape parses the four hexadecimal digits that follow a `\u` escape sequence.
+func parseUnicodeEscape(buf []byte) (rune, error) {
+	if len(buf) < 4 {
+		return 0, io.ErrShortBuffer
+	}
+	v, err := strconv.ParseUint(string(buf[:4]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("string: invalid unicode escape sequence: %q", buf[:4])
+	}
+	return rune(v), nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendTextString appends the JSON representation of s to buf, escaping characters as required
+// by JSON.
+func appendTextString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; b {
+		case '"', '\\':
+			buf = append(buf, '\\', b)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			if b < 0x20 {
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			} else {
+				buf = append(buf, b)
+			}
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendTextBytes appends the JSON representation of a sequence of bytes to buf.  Avro encodes
+// bytes and fixed values as JSON strings where each byte is mapped to the Unicode code point of the
+// same value, i.e., ISO-8859-1.  Bytes outside the printable ASCII range are written using `\u00XX`
+// escape sequences so the resulting text remains pure ASCII.
+func appendTextBytes(buf []byte, value []byte) []byte {
+	buf = append(buf, '"')
+	for _, b := range value {
+		switch {
+		case b == '"' || b == '\\':
+			buf = append(buf, '\\', b)
+		case b < 0x20 || b > 0x7e:
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+		default:
+			buf = append(buf, b)
+		}
+	}
+	return append(buf, '"')
+}
+
+// textDecodeBytes consumes a JSON string from the front of buf, and converts each of its code
+// points to a single byte.
+func textDecodeBytes(buf []byte) ([]byte, []byte, error) {
+	s, buf, err := textDecodeString(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := bytesFromCodePoints(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, buf, nil
+}
+
+// bytesFromCodePoints converts each code point of s to a single byte.
+func bytesFromCodePoints(s string) ([]byte, error) {
+	value := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			return nil, fmt.Errorf("code point ought to be between 0 and 255: %U", r)
+		}
+		value = append(value, byte(r))
+	}
+	return value, nil
+}
+
+// genericMapTextDecoder decodes a JSON object from buf into a Go map.  Each value is decoded using
+// the codec from codecFromKey, and if the key is not found in that map, using defaultCodec.  When
+// defaultCodec is nil, this function returns an error when it encounters a key not present in
+// codecFromKey.
+func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[string]*Codec) (map[string]interface{}, []byte, error) {
+	var err error
+	if buf, err = advanceAndConsume(buf, '{'); err != nil {
+		return nil, nil, err
+	}
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return nil, nil, err
+	}
+	mapValues := make(map[string]interface{}, len(codecFromKey))
+	if buf[0] == '}' {
+		return mapValues, buf[1:], nil
+	}
+	for {
+		var key string
+		if key, buf, err = textDecodeString(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode key: %s", err)
+		}
+		if _, ok := mapValues[key]; ok {
+			return nil, nil, fmt.Errorf("cannot decode duplicate key: %q", key)
+		}
+		fieldCodec, ok := codecFromKey[key]
+		if !ok {
+			if defaultCodec == nil {
+				return nil, nil, fmt.Errorf("cannot decode unknown key: %q", key)
+			}
+			fieldCodec = defaultCodec
+		}
+		if buf, err = advanceAndConsume(buf, ':'); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode value for key %q: %s", key, err)
+		}
+		var value interface{}
+		if value, buf, err = fieldCodec.textDecoder(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode value for key %q: %s", key, err)
+		}
+		mapValues[key] = value
+		if buf, err = advanceToNonWhitespace(buf); err != nil {
+			return nil, nil, err
+		}
+		switch buf[0] {
+		case '}':
+			return mapValues, buf[1:], nil
+		case ',':
+			buf = buf[1:]
+		default:
+			return nil, nil, fmt.Errorf("expected: ',' or '}'; actual: %q", buf[0])
+		}
+	}
+}
+
+// genericMapTextEncoder encodes a Go map as a JSON object, using the codec from codecFromKey for
+// each value, and if the key is not found in that map, using defaultCodec.  Keys are emitted in
+// sorted order so the same datum always produces the same text.
+func genericMapTextEncoder(buf []byte, datum map[string]interface{}, defaultCodec *Codec, codecFromKey map[string]*Codec) ([]byte, error) {
+	keys := make([]string, 0, len(datum))
+	for k := range datum {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var err error
+	buf = append(buf, '{')
+	for i, key := range keys {
+		fieldCodec, ok := codecFromKey[key]
+		if !ok {
+			if defaultCodec == nil {
+				return nil, fmt.Errorf("cannot encode unknown key: %q", key)
+			}
+			fieldCodec = defaultCodec
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendTextString(buf, key)
+		buf = append(buf, ':')
+		if buf, err = fieldCodec.textEncoder(buf, datum[key]); err != nil {
+			return nil, fmt.Errorf("cannot encode value for key %q: %v: %s", key, datum[key], err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// genericArrayTextDecoder decodes a JSON array from buf into a Go slice, using itemCodec to decode
+// each item.
+func genericArrayTextDecoder(buf []byte, itemCodec *Codec) ([]interface{}, []byte, error) {
+	var err error
+	if buf, err = advanceAndConsume(buf, '['); err != nil {
+		return nil, nil, err
+	}
+	if buf, err = advanceToNonWhitespace(buf); err != nil {
+		return nil, nil, err
+	}
+	arrayValues := make([]interface{}, 0)
+	if buf[0] == ']' {
+		return arrayValues, buf[1:], nil
+	}
+	for {
+		var value interface{}
+		if value, buf, err = itemCodec.textDecoder(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode item %d: %s", len(arrayValues)+1, err)
+		}
+		arrayValues = append(arrayValues, value)
+		if buf, err = advanceToNonWhitespace(buf); err != nil {
+			return nil, nil, err
+		}
+		switch buf[0] {
+		case ']':
+			return arrayValues, buf[1:], nil
+		case ',':
+			buf = buf[1:]
+		default:
+			return nil, nil, fmt.Errorf("expected: ',' or ']'; actual: %q", buf[0])
+		}
+	}
+}
+
+// genericArrayTextEncoder encodes a Go slice as a JSON array, using itemCodec to encode each item.
+func genericArrayTextEncoder(buf []byte, arrayValues []interface{}, itemCodec *Codec) ([]byte, error) {
+	var err error
+	buf = append(buf, '[')
+	for i, item := range arrayValues {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		if buf, err = itemCodec.textEncoder(buf, item); err != nil {
+			return nil, fmt.Errorf("cannot encode item %d; %v: %s", i+1, item, err)
+		}
+	}
+	return append(buf, ']'), nil
+}
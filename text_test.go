@@ -0,0 +1,103 @@
+package goavro_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+func TestTextPrimitives(t *testing.T) {
+	testTextCodecPass(t, "null", nil, []byte("null"))
+	testTextCodecPass(t, "boolean", true, []byte("true"))
+	testTextCodecPass(t, "boolean", false, []byte("false"))
+	testTextCodecPass(t, "int", int32(-13), []byte("-13"))
+	testTextCodecPass(t, "long", int64(1<<40), []byte("1099511627776"))
+	testTextCodecPass(t, "float", float32(3.5), []byte("3.5"))
+	testTextCodecPass(t, "double", 3.5, []byte("3.5"))
+	testTextCodecPass(t, "double", math.Inf(-1), []byte(`"-Infinity"`))
+	testTextCodecPass(t, "string", "some string", []byte(`"some string"`))
+	testTextCodecPass(t, "string", "quote \" and \\ and \n", []byte(`"quote \" and \\ and \n"`))
+}
+
+func TestTextDecodeWhitespace(t *testing.T) {
+	testTextDecodePass(t, "int", int32(42), []byte(" \t\n42"))
+	testTextDecodePass(t, `{"type":"array","items":"int"}`, []interface{}{int32(1), int32(2)}, []byte(" [ 1 , 2 ]"))
+}
+
+func TestTextDecodeFail(t *testing.T) {
+	testTextDecodeFail(t, "null", []byte("none"), "null: expected")
+	testTextDecodeFail(t, "boolean", []byte("1"), "boolean: expected: true or false")
+	testTextDecodeFail(t, "int", []byte("2147483648"), "int: ")
+	testTextDecodeFail(t, "int", nil, "short buffer")
+	testTextDecodeFail(t, "string", []byte(`"unterminated`), "short buffer")
+	testTextDecodeFail(t, "string", []byte(`"bad \x escape"`), "invalid escape sequence")
+}
+
+func TestTextStringUnicode(t *testing.T) {
+	testTextDecodePass(t, "string", "é\U0001F600", []byte(`"é😀"`))
+	testTextEncodePass(t, "string", "é", []byte("\"é\""))
+}
+
+func TestTextBytes(t *testing.T) {
+	testTextCodecPass(t, "bytes", []byte("abc"), []byte(`"abc"`))
+	testTextCodecPass(t, "bytes", []byte{0, 0x7f, 0xff}, []byte(`"\u0000\u007f\u00ff"`))
+	testTextDecodePass(t, "bytes", []byte{0xe9}, []byte("\"é\"")) // ISO-8859-1 code point written as raw UTF-8
+	testTextDecodeFail(t, "bytes", []byte(`"Ā"`), "code point ought to be between 0 and 255")
+}
+
+func TestTextFixed(t *testing.T) {
+	testTextCodecPass(t, `{"type":"fixed","name":"f1","size":2}`, []byte{0xff, 'a'}, []byte(`"\u00ffa"`))
+	testTextDecodeFail(t, `{"type":"fixed","name":"f1","size":2}`, []byte(`"abc"`), "datum length ought to equal size")
+	testTextEncodeFail(t, `{"type":"fixed","name":"f1","size":2}`, "abc", "datum length ought to equal size")
+}
+
+func TestTextEnum(t *testing.T) {
+	testTextCodecPass(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, "bravo", []byte(`"bravo"`))
+	testTextDecodeFail(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, []byte(`"charlie"`), "value ought to be member of symbols")
+}
+
+func TestTextArray(t *testing.T) {
+	testTextCodecPass(t, `{"type":"array","items":"int"}`, []interface{}{}, []byte("[]"))
+	testTextCodecPass(t, `{"type":"array","items":"int"}`, []interface{}{int32(1), int32(2)}, []byte("[1,2]"))
+	testTextEncodePass(t, `{"type":"array","items":"int"}`, []int{1, 2}, []byte("[1,2]"))
+	testTextDecodeFail(t, `{"type":"array","items":"int"}`, []byte("[1,2"), "short buffer")
+}
+
+func TestTextMap(t *testing.T) {
+	testTextCodecPass(t, `{"type":"map","values":"int"}`, map[string]interface{}{}, []byte("{}"))
+	testTextCodecPass(t, `{"type":"map","values":"int"}`, map[string]interface{}{"b": int32(2), "a": int32(1)}, []byte(`{"a":1,"b":2}`))
+	testTextDecodeFail(t, `{"type":"map","values":"int"}`, []byte(`{"a":1,"a":2}`), "duplicate key")
+}
+
+func TestTextRecord(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":"string"},{"name":"f2","type":"int"}]}`
+	testTextCodecPass(t, schema, map[string]interface{}{"f1": "thirteen", "f2": int32(13)}, []byte(`{"f1":"thirteen","f2":13}`))
+	testTextDecodePass(t, schema, map[string]interface{}{"f1": "thirteen", "f2": int32(13)}, []byte(`{ "f2" : 13 , "f1" : "thirteen" }`))
+	testTextDecodeFail(t, schema, []byte(`{"f1":"thirteen"}`), `field value for "f2" was not specified`)
+	testTextDecodeFail(t, schema, []byte(`{"f1":"thirteen","f2":13,"f3":true}`), `unknown key: "f3"`)
+	testTextEncodeFail(t, schema, map[string]interface{}{"f1": "thirteen"}, `field value for "f2" was not specified`)
+}
+
+func TestTextUnion(t *testing.T) {
+	testTextCodecPass(t, `["null","string"]`, nil, []byte("null"))
+	testTextCodecPass(t, `["null","string"]`, goavro.Union("string", "x"), []byte(`{"string":"x"}`))
+	testTextCodecPass(t, `["null",{"type":"enum","name":"com.example.e1","symbols":["alpha","bravo"]}]`, goavro.Union("com.example.e1", "alpha"), []byte(`{"com.example.e1":"alpha"}`))
+	testTextDecodeFail(t, `["string","int"]`, []byte("null"), "no member schema types support null")
+	testTextDecodeFail(t, `["null","string"]`, []byte(`{"int":3}`), "no member schema types support datum")
+	testTextEncodeFail(t, `["null","string"]`, goavro.Union("int", 3), "no member schema types support datum")
+}
+
+func TestTextEncodeAppends(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type":"array","items":"long"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := codec.TextEncode([]byte("prefix:"), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := string(buf), "prefix:[1,2,3]"; actual != expected {
+		t.Errorf("Actual: %#v; Expected: %#v", actual, expected)
+	}
+}
@@ -0,0 +1,103 @@
+package goavro
+
+import (
+	"time"
+)
+
+// NOTE: The date, time, and timestamp logical types decode to time.Time or time.Duration values.
+// Their encoders accept those types, and pass any other datum through to the encoder for the
+// underlying int or long type, so clients may continue to provide raw numeric values.  When
+// encoding, values are truncated to the precision of the logical type.
+
+const secondsPerDay = 24 * 60 * 60
+
+// date annotates an int, storing the number of days since the Unix epoch.  It decodes to a
+// time.Time at midnight UTC.
+func dateToNative(raw interface{}) (interface{}, error) {
+	return time.Unix(int64(raw.(int32))*secondsPerDay, 0).UTC(), nil // int decoder always returns int32
+}
+
+func dateFromNative(datum interface{}) (interface{}, error) {
+	t, ok := datum.(time.Time)
+	if !ok {
+		return datum, nil
+	}
+	// NOTE: A date has no time zone, so use the calendar date in the location of the datum.
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay, nil
+}
+
+// time-millis annotates an int, storing the number of milliseconds after midnight.
+func timeMillisToNative(raw interface{}) (interface{}, error) {
+	return time.Duration(raw.(int32)) * time.Millisecond, nil // int decoder always returns int32
+}
+
+func timeMillisFromNative(datum interface{}) (interface{}, error) {
+	if d, ok := datum.(time.Duration); ok {
+		return int64(d / time.Millisecond), nil // int encoder rejects values that overflow int32
+	}
+	return datum, nil
+}
+
+// time-micros annotates a long, storing the number of microseconds after midnight.
+func timeMicrosToNative(raw interface{}) (interface{}, error) {
+	return time.Duration(raw.(int64)) * time.Microsecond, nil // long decoder always returns int64
+}
+
+func timeMicrosFromNative(datum interface{}) (interface{}, error) {
+	if d, ok := datum.(time.Duration); ok {
+		return int64(d / time.Microsecond), nil
+	}
+	return datum, nil
+}
+
+// timestamp-millis annotates a long, storing the number of milliseconds since the Unix epoch.  It
+// decodes to a time.Time in UTC.
+func timestampMillisToNative(raw interface{}) (interface{}, error) {
+	return time.UnixMilli(raw.(int64)).UTC(), nil // long decoder always returns int64
+}
+
+func timestampMillisFromNative(datum interface{}) (interface{}, error) {
+	if t, ok := datum.(time.Time); ok {
+		return t.UnixMilli(), nil
+	}
+	return datum, nil
+}
+
+// timestamp-micros annotates a long, storing the number of microseconds since the Unix epoch.  It
+// decodes to a time.Time in UTC.
+func timestampMicrosToNative(raw interface{}) (interface{}, error) {
+	return time.UnixMicro(raw.(int64)).UTC(), nil // long decoder always returns int64
+}
+
+func timestampMicrosFromNative(datum interface{}) (interface{}, error) {
+	if t, ok := datum.(time.Time); ok {
+		return t.UnixMicro(), nil
+	}
+	return datum, nil
+}
+
+// local-timestamp-millis and local-timestamp-micros store a wall clock reading without a time
+// zone.  They decode to a time.Time in UTC whose wall clock matches the encoded value, and encode
+// the wall clock reading of the provided time.Time, regardless of its location.
+
+func localTimestampMillisFromNative(datum interface{}) (interface{}, error) {
+	if t, ok := datum.(time.Time); ok {
+		return wallClockAsUTC(t).UnixMilli(), nil
+	}
+	return datum, nil
+}
+
+func localTimestampMicrosFromNative(datum interface{}) (interface{}, error) {
+	if t, ok := datum.(time.Time); ok {
+		return wallClockAsUTC(t).UnixMicro(), nil
+	}
+	return datum, nil
+}
+
+// wallClockAsUTC returns a time.Time in UTC with the same wall clock reading as t.
+func wallClockAsUTC(t time.Time) time.Time {
+	y, m, d := t.Date()
+	hh, mm, ss := t.Clock()
+	return time.Date(y, m, d, hh, mm, ss, t.Nanosecond(), time.UTC)
+}
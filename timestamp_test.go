@@ -0,0 +1,59 @@
+package goavro_test
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	schema := `{"type":"int","logicalType":"date"}`
+	testBinaryCodecPass(t, schema, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), []byte("\x00"))
+	testBinaryCodecPass(t, schema, time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), []byte("\x02"))
+	testBinaryCodecPass(t, schema, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), []byte("\x01"))
+	// calendar date of the datum, regardless of its location or time of day
+	testBinaryEncodePass(t, schema, time.Date(1970, 1, 2, 23, 0, 0, 0, time.FixedZone("UTC-8", -8*60*60)), []byte("\x02"))
+	// raw underlying values are still accepted
+	testBinaryEncodePass(t, schema, 1, []byte("\x02"))
+	testTextCodecPass(t, schema, time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), []byte("1"))
+}
+
+func TestTimeMillis(t *testing.T) {
+	schema := `{"type":"int","logicalType":"time-millis"}`
+	testBinaryCodecPass(t, schema, 1500*time.Millisecond, []byte("\xb8\x17"))
+	testBinaryEncodePass(t, schema, 1500*time.Millisecond+999*time.Microsecond, []byte("\xb8\x17")) // truncated
+	testBinaryEncodeFail(t, schema, 1000*time.Hour, "lose precision")
+}
+
+func TestTimeMicros(t *testing.T) {
+	schema := `{"type":"long","logicalType":"time-micros"}`
+	testBinaryCodecPass(t, schema, 1500*time.Microsecond, []byte("\xb8\x17"))
+}
+
+func TestTimestampMillis(t *testing.T) {
+	schema := `{"type":"long","logicalType":"timestamp-millis"}`
+	testBinaryCodecPass(t, schema, time.Date(1970, 1, 1, 0, 0, 1, 500000000, time.UTC), []byte("\xb8\x17"))
+	testBinaryCodecPass(t, schema, time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC), []byte("\x01"))
+	// instant is preserved regardless of location
+	testBinaryEncodePass(t, schema, time.Date(1970, 1, 1, 1, 0, 1, 500000000, time.FixedZone("UTC+1", 60*60)), []byte("\xb8\x17"))
+}
+
+func TestTimestampMicros(t *testing.T) {
+	schema := `{"type":"long","logicalType":"timestamp-micros"}`
+	testBinaryCodecPass(t, schema, time.Date(1970, 1, 1, 0, 0, 0, 1500000, time.UTC), []byte("\xb8\x17"))
+	testTextCodecPass(t, schema, time.Date(2017, 1, 2, 3, 4, 5, 6000, time.UTC), []byte("1483326245000006"))
+}
+
+func TestLocalTimestamp(t *testing.T) {
+	// wall clock reading is preserved regardless of location
+	local := time.Date(1970, 1, 1, 0, 0, 1, 500000000, time.FixedZone("UTC+1", 60*60))
+	testBinaryEncodePass(t, `{"type":"long","logicalType":"local-timestamp-millis"}`, local, []byte("\xb8\x17"))
+	testBinaryEncodePass(t, `{"type":"long","logicalType":"local-timestamp-micros"}`, local, []byte("\xc0\x8d\xb7\x01"))
+	testBinaryDecodePass(t, `{"type":"long","logicalType":"local-timestamp-millis"}`, time.Date(1970, 1, 1, 0, 0, 1, 500000000, time.UTC), []byte("\xb8\x17"))
+}
+
+func TestUnknownLogicalTypeUsesUnderlyingType(t *testing.T) {
+	testBinaryCodecPass(t, `{"type":"long","logicalType":"no-such-logical-type"}`, int64(3), []byte("\x06"))
+	// known logical type on wrong underlying type
+	testBinaryCodecPass(t, `{"type":"string","logicalType":"date"}`, "x", []byte("\x02x"))
+	testBinaryCodecPass(t, `{"type":"long","logicalType":"date"}`, int64(3), []byte("\x06"))
+}
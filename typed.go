@@ -0,0 +1,70 @@
+//go:build go1.18
+
+package goavro
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedCodec encodes and decodes values of the Go type T in accordance with the Avro schema of a
+// Codec, using the same mapping between Go values and Avro types as Marshal and Unmarshal.  Unlike
+// BinaryDecode, which returns an interface{} that ought to be asserted to the expected type, Decode
+// returns a value of type T.
+//
+// A TypedCodec is safe for concurrent use by multiple goroutines.
+type TypedCodec[T any] struct {
+	codec *Codec
+	plan  *reflectPlan
+}
+
+// NewTypedCodec returns a TypedCodec for values of the Go type T, and the schema of the Codec,
+// which may be a resolving Codec returned by NewResolvingCodec.  It verifies that T is compatible
+// with the schema, and returns an error when it is not.  In addition to the verification done by
+// Marshal, each record field without a default value ought to have a matching struct field.
+//
+//	codec, err := goavro.NewCodec(schema)
+//	if err != nil {
+//	    return err
+//	}
+//	users, err := goavro.NewTypedCodec[User](codec)
+//	if err != nil {
+//	    return err
+//	}
+//	buf, err := users.Encode(nil, User{Name: "Alice"})
+func NewTypedCodec[T any](codec *Codec) (*TypedCodec[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	plan, err := buildCodecPlan(codec, t, newReflectPlans(true))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create TypedCodec for Go type %s: %s", t, err)
+	}
+	return &TypedCodec[T]{codec: codec, plan: plan}, nil
+}
+
+// Codec returns the Codec whose schema the TypedCodec uses.
+func (tc *TypedCodec[T]) Codec() *Codec {
+	return tc.codec
+}
+
+// Encode appends the binary encoding of v to buf, and returns the new byte slice.  On error, it
+// returns the original byte slice without any encoded bytes.
+func (tc *TypedCodec[T]) Encode(buf []byte, v T) ([]byte, error) {
+	newBuf, err := tc.plan.encode(buf, reflect.ValueOf(&v).Elem())
+	if err != nil {
+		return buf, fmt.Errorf("cannot encode Go type %s: %s", reflect.TypeOf(&v).Elem(), err)
+	}
+	return newBuf, nil
+}
+
+// Decode decodes the binary encoded datum at the start of buf, and returns the decoded value and
+// the byte slice with the decoded bytes consumed.  On error, it returns the zero value of T, the
+// original byte slice without any bytes consumed, and the error.
+func (tc *TypedCodec[T]) Decode(buf []byte) (T, []byte, error) {
+	var v T
+	newBuf, err := tc.plan.decode(buf, reflect.ValueOf(&v).Elem())
+	if err != nil {
+		var zero T
+		return zero, buf, fmt.Errorf("cannot decode into Go type %s: %s", reflect.TypeOf(&v).Elem(), err)
+	}
+	return v, newBuf, nil
+}
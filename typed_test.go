@@ -0,0 +1,209 @@
+//go:build go1.18
+
+package goavro_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karrick/goavro"
+)
+
+type typedUser struct {
+	Name  string   `avro:"name"`
+	Age   int      `avro:"age"`
+	Email *string  `avro:"email"`
+	Tags  []string `avro:"tags"`
+}
+
+const typedUserSchema = `{"type":"record","name":"user","fields":[
+	{"name":"name","type":"string"},
+	{"name":"age","type":"int"},
+	{"name":"email","type":["null","string"],"default":null},
+	{"name":"tags","type":{"type":"array","items":"string"}}]}`
+
+func TestTypedCodecRoundTrip(t *testing.T) {
+	codec, err := goavro.NewCodec(typedUserSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := goavro.NewTypedCodec[typedUser](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "a@example.com"
+	user := typedUser{Name: "alice", Age: 42, Email: &email, Tags: []string{"x", "y"}}
+
+	buf, err := tc.Encode([]byte("prefix"), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := codec.Marshal([]byte("prefix"), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Actual: %v; Expected: %v", buf, expected)
+	}
+
+	decoded, rest, err := tc.Decode(append(buf[len("prefix"):], 0xff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if decoded.Name != user.Name || decoded.Age != user.Age || decoded.Email == nil || *decoded.Email != email || len(decoded.Tags) != 2 {
+		t.Errorf("Actual: %v; Expected: %v", decoded, user)
+	}
+}
+
+func TestTypedCodecPrimitive(t *testing.T) {
+	codec, err := goavro.NewCodec(`"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := goavro.NewTypedCodec[int64](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := tc.Encode(nil, -3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf, []byte{0x05}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	value, _, err := tc.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, int64(-3); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestTypedCodecResolving(t *testing.T) {
+	type reader struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	codec, err := goavro.NewResolvingCodec(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"c","type":"string"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bee"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := goavro.NewTypedCodec[reader](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, rest, err := tc.Decode([]byte("\x06\x02c\xff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := rest, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+	if actual, expected := decoded, (reader{A: 3, B: "bee"}); actual != expected {
+		t.Errorf("Actual: %v; Expected: %v", actual, expected)
+	}
+}
+
+func TestTypedCodecResolvingGeneric(t *testing.T) {
+	codec, err := goavro.NewResolvingCodec(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"c","type":"string"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"bee"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"a": int64(3), "b": "bee"}
+
+	maps, err := goavro.NewTypedCodec[map[string]interface{}](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _, err := maps.Decode([]byte("\x06\x02c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Actual: %v; Expected: %v", m, expected)
+	}
+
+	ifaces, err := goavro.NewTypedCodec[interface{}](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _, err := ifaces.Decode([]byte("\x06\x02c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Actual: %v; Expected: %v", v, expected)
+	}
+}
+
+func TestTypedCodecIncompatible(t *testing.T) {
+	codec, err := goavro.NewCodec(typedUserSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = goavro.NewTypedCodec[string](codec)
+	if err == nil || !strings.Contains(err.Error(), "cannot use Go type string with Avro type user") {
+		t.Errorf("Actual: %v; Expected: %s", err, "cannot use Go type string")
+	}
+
+	type withoutAge struct {
+		Name string `avro:"name"`
+	}
+	_, err = goavro.NewTypedCodec[withoutAge](codec)
+	if err == nil || !strings.Contains(err.Error(), `Record "user" field "age" ought to have matching field`) {
+		t.Errorf("Actual: %v; Expected: %s", err, "ought to have matching field")
+	}
+
+	type wrongAge struct {
+		Name string  `avro:"name"`
+		Age  float64 `avro:"age"`
+		Tags []string
+	}
+	_, err = goavro.NewTypedCodec[wrongAge](codec)
+	if err == nil || !strings.Contains(err.Error(), `Record "user" field "age"`) {
+		t.Errorf("Actual: %v; Expected: %s", err, `Record "user" field "age"`)
+	}
+
+	// NOTE: A record field with a default value does not need a matching struct field.
+	type withoutEmail struct {
+		Name string   `avro:"name"`
+		Age  int32    `avro:"age"`
+		Tags []string `avro:"tags"`
+	}
+	if _, err = goavro.NewTypedCodec[withoutEmail](codec); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTypedCodecDecodeFail(t *testing.T) {
+	codec, err := goavro.NewCodec(typedUserSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := goavro.NewTypedCodec[typedUser](codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte("\x0aalice")
+	value, rest, err := tc.Decode(buf)
+	if err == nil {
+		t.Fatalf("Actual: %v; Expected: %s", err, "error")
+	}
+	if value.Name != "" {
+		t.Errorf("Actual: %v; Expected: %v", value, typedUser{})
+	}
+	if !bytes.Equal(rest, buf) {
+		t.Errorf("Actual: %v; Expected: %v", rest, buf)
+	}
+}
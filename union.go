@@ -38,6 +38,8 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 
 	return &Codec{
 		typeName: &name{"union", nullNamespace},
+		kind:     "union",
+		members:  codecFromIndex,
 		binaryDecoder: func(buf []byte) (interface{}, []byte, error) {
 			var decoded interface{}
 			var err error
@@ -60,6 +62,19 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 			}
 			return map[string]interface{}{allowedTypes[index]: decoded}, buf, nil
 		},
+		binarySkipper: func(buf []byte) ([]byte, error) {
+			index, rest, err := ReadLong(buf)
+			if err != nil {
+				return buf, err
+			}
+			if index < 0 || index >= int64(len(codecFromIndex)) {
+				return buf, fmt.Errorf("cannot skip Union: index ought to be between 0 and %d; read index: %d", len(codecFromIndex)-1, index)
+			}
+			if rest, err = codecFromIndex[index].binarySkipper(rest); err != nil {
+				return buf, fmt.Errorf("cannot skip Union item %d: %s", index+1, err)
+			}
+			return rest, nil
+		},
 		binaryEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
 			switch v := datum.(type) {
 			case nil:
@@ -85,5 +100,79 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 			}
 			return buf, fmt.Errorf("cannot encode Union: non-nil values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
 		},
+		textDecoder: func(buf []byte) (interface{}, []byte, error) {
+			// NOTE: Avro encodes a null union value as JSON null, and every other value as a
+			// JSON object with a single key equal to the type name of the member schema.
+			if hasPrefixLiteral(buf, "null") {
+				if _, ok := indexFromName["null"]; !ok {
+					return nil, buf, fmt.Errorf("cannot decode textual Union: no member schema types support null: allowed types: %v", allowedTypes)
+				}
+				return nullTextDecoder(buf)
+			}
+			var err error
+			if buf, err = advanceAndConsume(buf, '{'); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Union: %s", err)
+			}
+			var key string
+			if key, buf, err = textDecodeString(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Union: %s", err)
+			}
+			index, ok := indexFromName[key]
+			if !ok {
+				return nil, buf, fmt.Errorf("cannot decode textual Union: no member schema types support datum: allowed types: %v; received: %q", allowedTypes, key)
+			}
+			if buf, err = advanceAndConsume(buf, ':'); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Union: %s", err)
+			}
+			var decoded interface{}
+			if decoded, buf, err = codecFromIndex[index].textDecoder(buf); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Union item %d: %s", index+1, err)
+			}
+			if buf, err = advanceAndConsume(buf, '}'); err != nil {
+				return nil, buf, fmt.Errorf("cannot decode textual Union: %s", err)
+			}
+			return map[string]interface{}{key: decoded}, buf, nil
+		},
+		textEncoder: func(buf []byte, datum interface{}) ([]byte, error) {
+			switch v := datum.(type) {
+			case nil:
+				if _, ok := indexFromName["null"]; !ok {
+					return buf, fmt.Errorf("cannot encode textual Union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
+				}
+				return nullTextEncoder(buf, nil)
+			case map[string]interface{}:
+				if len(v) != 1 {
+					return buf, fmt.Errorf("cannot encode textual Union: non-nil values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
+				}
+				// will execute exactly once
+				for key, value := range v {
+					index, ok := indexFromName[key]
+					if !ok {
+						return buf, fmt.Errorf("cannot encode textual Union: no member schema types support datum: allowed types: %v; received: %T", allowedTypes, datum)
+					}
+					buf = append(buf, '{')
+					buf = appendTextString(buf, key)
+					buf = append(buf, ':')
+					var err error
+					if buf, err = codecFromIndex[index].textEncoder(buf, value); err != nil {
+						return buf, err
+					}
+					return append(buf, '}'), nil
+				}
+			}
+			return buf, fmt.Errorf("cannot encode textual Union: non-nil values ought to be specified with Go map[string]interface{}, with single key equal to type name, and value equal to datum value: %v; received: %T", allowedTypes, datum)
+		},
+		defaultDecoder: func(datum interface{}) (interface{}, error) {
+			// NOTE: The default value of a union corresponds to the first member schema of the
+			// union, and is not wrapped with the type name in the schema.
+			value, err := decodeDefault(codecFromIndex[0], datum)
+			if err != nil {
+				return nil, fmt.Errorf("Union default value ought to match first member type %q: %s", allowedTypes[0], err)
+			}
+			if value == nil {
+				return nil, nil
+			}
+			return map[string]interface{}{allowedTypes[0]: value}, nil
+		},
 	}, nil
 }
@@ -0,0 +1,70 @@
+package goavro
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// uuidTextLength is the length of the canonical textual representation of a UUID, namely 32
+// hexadecimal digits in five groups separated by hyphens, as 8-4-4-4-12.
+const uuidTextLength = 36
+
+// checkUUID returns an error when s is not the canonical textual representation of a UUID.
+func checkUUID(s string) error {
+	if len(s) != uuidTextLength {
+		return fmt.Errorf("uuid: length ought to be %d: %q", uuidTextLength, s)
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return fmt.Errorf("uuid: expected hyphen at index %d: %q", i, s)
+			}
+		default:
+			if c := s[i]; !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+				return fmt.Errorf("uuid: expected hexadecimal digit at index %d: %q", i, s)
+			}
+		}
+	}
+	return nil
+}
+
+// formatUUID returns the canonical textual representation of the 16 byte UUID.
+func formatUUID(u [16]byte) string {
+	buf := make([]byte, uuidTextLength)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// uuid annotates a string, which must be the canonical textual representation of a UUID.  It
+// decodes to a string, and encodes either a string or a [16]byte, the latter being formatted using
+// lowercase hexadecimal digits.
+func uuidToNative(raw interface{}) (interface{}, error) {
+	s := raw.(string) // string decoder always returns string
+	if err := checkUUID(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func uuidFromNative(datum interface{}) (interface{}, error) {
+	switch v := datum.(type) {
+	case string:
+		if err := checkUUID(v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case [16]byte:
+		return formatUUID(v), nil
+	default:
+		return nil, fmt.Errorf("uuid: expected: Go string or [16]byte; received: %T", datum)
+	}
+}
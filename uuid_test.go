@@ -0,0 +1,21 @@
+package goavro_test
+
+import (
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	schema := `{"type":"string","logicalType":"uuid"}`
+	testBinaryCodecPass(t, schema, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", []byte("\x486ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	testBinaryEncodePass(t, schema, [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}, []byte("\x486ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	testTextCodecPass(t, schema, "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", []byte(`"6BA7B810-9DAD-11D1-80B4-00C04FD430C8"`))
+}
+
+func TestUUIDInvalid(t *testing.T) {
+	schema := `{"type":"string","logicalType":"uuid"}`
+	testBinaryEncodeFail(t, schema, "6ba7b810-9dad-11d1-80b4-00c04fd430c", "length ought to be 36")
+	testBinaryEncodeFail(t, schema, "6ba7b810x9dad-11d1-80b4-00c04fd430c8", "expected hyphen at index 8")
+	testBinaryEncodeFail(t, schema, "6ba7b810-9dad-11d1-80b4-00c04fd430cg", "expected hexadecimal digit at index 35")
+	testBinaryEncodeFail(t, schema, 13, "expected: Go string or [16]byte")
+	testBinaryDecodeFail(t, schema, []byte("\x06abc"), "length ought to be 36")
+}